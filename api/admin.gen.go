@@ -9,13 +9,113 @@ import (
 	"github.com/pacedotdev/oto/otohttp"
 )
 
+type BackchannelService interface {
+	CompleteBackchannelAuthentication(context.Context, CompleteBackchannelAuthenticationRequest) (*CompleteBackchannelAuthenticationResponse, error)
+	RejectBackchannelAuthentication(context.Context, RejectBackchannelAuthenticationRequest) (*RejectBackchannelAuthenticationResponse, error)
+	ShowBackchannelAuthentication(context.Context, ShowBackchannelAuthenticationRequest) (*ShowBackchannelAuthenticationResponse, error)
+}
+
 type ConsentService interface {
 	GrantConsent(context.Context, GrantConsentRequest) (*GrantConsentResponse, error)
+	ListConsentHistory(context.Context, ListConsentHistoryRequest) (*ListConsentHistoryResponse, error)
+	ListConsentsBySubject(context.Context, ListConsentsBySubjectRequest) (*ListConsentsBySubjectResponse, error)
+	RejectConsent(context.Context, RejectConsentRequest) (*RejectConsentResponse, error)
+	RevokeConsent(context.Context, RevokeConsentRequest) (*RevokeConsentResponse, error)
+	RevokeScopes(context.Context, RevokeScopesRequest) (*RevokeScopesResponse, error)
 	ShowConsentChallenge(context.Context, ShowConsentChallengeRequest) (*ShowConsentChallengeResponse, error)
 }
 
 type IdentityService interface {
 	Authenticate(context.Context, AuthenticateRequest) (*AuthenticateResponse, error)
+	RejectLogin(context.Context, RejectLoginRequest) (*RejectLoginResponse, error)
+	ShowLoginChallenge(context.Context, ShowLoginChallengeRequest) (*ShowLoginChallengeResponse, error)
+}
+
+type ScopeService interface {
+	DeleteScope(context.Context, DeleteScopeRequest) (*DeleteScopeResponse, error)
+	GetScope(context.Context, GetScopeRequest) (*GetScopeResponse, error)
+	ListScopes(context.Context, ListScopesRequest) (*ListScopesResponse, error)
+	PutScope(context.Context, PutScopeRequest) (*PutScopeResponse, error)
+}
+
+type SecurityService interface {
+	ClearLockout(context.Context, ClearLockoutRequest) (*ClearLockoutResponse, error)
+	ListLockouts(context.Context, ListLockoutsRequest) (*ListLockoutsResponse, error)
+	ListViolations(context.Context, ListViolationsRequest) (*ListViolationsResponse, error)
+}
+
+type SessionService interface {
+	GetSession(context.Context, GetSessionRequest) (*GetSessionResponse, error)
+	ListSessionsBySubject(context.Context, ListSessionsBySubjectRequest) (*ListSessionsBySubjectResponse, error)
+	RevokeAllSessionsForSubject(context.Context, RevokeAllSessionsForSubjectRequest) (*RevokeAllSessionsForSubjectResponse, error)
+	RevokeSession(context.Context, RevokeSessionRequest) (*RevokeSessionResponse, error)
+}
+
+type backchannelServiceServer struct {
+	server             *otohttp.Server
+	backchannelService BackchannelService
+}
+
+// Register adds the BackchannelService to the otohttp.Server.
+func RegisterBackchannelService(server *otohttp.Server, backchannelService BackchannelService) {
+	handler := &backchannelServiceServer{
+		server:             server,
+		backchannelService: backchannelService,
+	}
+	server.Register("BackchannelService", "CompleteBackchannelAuthentication", handler.handleCompleteBackchannelAuthentication)
+	server.Register("BackchannelService", "RejectBackchannelAuthentication", handler.handleRejectBackchannelAuthentication)
+	server.Register("BackchannelService", "ShowBackchannelAuthentication", handler.handleShowBackchannelAuthentication)
+}
+
+func (s *backchannelServiceServer) handleCompleteBackchannelAuthentication(w http.ResponseWriter, r *http.Request) {
+	var request CompleteBackchannelAuthenticationRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.backchannelService.CompleteBackchannelAuthentication(r.Context(), request)
+	if err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+func (s *backchannelServiceServer) handleRejectBackchannelAuthentication(w http.ResponseWriter, r *http.Request) {
+	var request RejectBackchannelAuthenticationRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.backchannelService.RejectBackchannelAuthentication(r.Context(), request)
+	if err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+func (s *backchannelServiceServer) handleShowBackchannelAuthentication(w http.ResponseWriter, r *http.Request) {
+	var request ShowBackchannelAuthenticationRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.backchannelService.ShowBackchannelAuthentication(r.Context(), request)
+	if err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
 }
 
 type consentServiceServer struct {
@@ -30,6 +130,11 @@ func RegisterConsentService(server *otohttp.Server, consentService ConsentServic
 		consentService: consentService,
 	}
 	server.Register("ConsentService", "GrantConsent", handler.handleGrantConsent)
+	server.Register("ConsentService", "ListConsentHistory", handler.handleListConsentHistory)
+	server.Register("ConsentService", "ListConsentsBySubject", handler.handleListConsentsBySubject)
+	server.Register("ConsentService", "RejectConsent", handler.handleRejectConsent)
+	server.Register("ConsentService", "RevokeConsent", handler.handleRevokeConsent)
+	server.Register("ConsentService", "RevokeScopes", handler.handleRevokeScopes)
 	server.Register("ConsentService", "ShowConsentChallenge", handler.handleShowConsentChallenge)
 }
 
@@ -50,6 +155,91 @@ func (s *consentServiceServer) handleGrantConsent(w http.ResponseWriter, r *http
 	}
 }
 
+func (s *consentServiceServer) handleListConsentHistory(w http.ResponseWriter, r *http.Request) {
+	var request ListConsentHistoryRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.consentService.ListConsentHistory(r.Context(), request)
+	if err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+func (s *consentServiceServer) handleListConsentsBySubject(w http.ResponseWriter, r *http.Request) {
+	var request ListConsentsBySubjectRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.consentService.ListConsentsBySubject(r.Context(), request)
+	if err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+func (s *consentServiceServer) handleRejectConsent(w http.ResponseWriter, r *http.Request) {
+	var request RejectConsentRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.consentService.RejectConsent(r.Context(), request)
+	if err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+func (s *consentServiceServer) handleRevokeConsent(w http.ResponseWriter, r *http.Request) {
+	var request RevokeConsentRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.consentService.RevokeConsent(r.Context(), request)
+	if err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+func (s *consentServiceServer) handleRevokeScopes(w http.ResponseWriter, r *http.Request) {
+	var request RevokeScopesRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.consentService.RevokeScopes(r.Context(), request)
+	if err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
 func (s *consentServiceServer) handleShowConsentChallenge(w http.ResponseWriter, r *http.Request) {
 	var request ShowConsentChallengeRequest
 	if err := otohttp.Decode(r, &request); err != nil {
@@ -79,6 +269,8 @@ func RegisterIdentityService(server *otohttp.Server, identityService IdentitySer
 		identityService: identityService,
 	}
 	server.Register("IdentityService", "Authenticate", handler.handleAuthenticate)
+	server.Register("IdentityService", "RejectLogin", handler.handleRejectLogin)
+	server.Register("IdentityService", "ShowLoginChallenge", handler.handleShowLoginChallenge)
 }
 
 func (s *identityServiceServer) handleAuthenticate(w http.ResponseWriter, r *http.Request) {
@@ -98,6 +290,277 @@ func (s *identityServiceServer) handleAuthenticate(w http.ResponseWriter, r *htt
 	}
 }
 
+func (s *identityServiceServer) handleRejectLogin(w http.ResponseWriter, r *http.Request) {
+	var request RejectLoginRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.identityService.RejectLogin(r.Context(), request)
+	if err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+func (s *identityServiceServer) handleShowLoginChallenge(w http.ResponseWriter, r *http.Request) {
+	var request ShowLoginChallengeRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.identityService.ShowLoginChallenge(r.Context(), request)
+	if err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+type scopeServiceServer struct {
+	server       *otohttp.Server
+	scopeService ScopeService
+}
+
+// Register adds the ScopeService to the otohttp.Server.
+func RegisterScopeService(server *otohttp.Server, scopeService ScopeService) {
+	handler := &scopeServiceServer{
+		server:       server,
+		scopeService: scopeService,
+	}
+	server.Register("ScopeService", "DeleteScope", handler.handleDeleteScope)
+	server.Register("ScopeService", "GetScope", handler.handleGetScope)
+	server.Register("ScopeService", "ListScopes", handler.handleListScopes)
+	server.Register("ScopeService", "PutScope", handler.handlePutScope)
+}
+
+func (s *scopeServiceServer) handleDeleteScope(w http.ResponseWriter, r *http.Request) {
+	var request DeleteScopeRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.scopeService.DeleteScope(r.Context(), request)
+	if err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+func (s *scopeServiceServer) handleGetScope(w http.ResponseWriter, r *http.Request) {
+	var request GetScopeRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.scopeService.GetScope(r.Context(), request)
+	if err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+func (s *scopeServiceServer) handleListScopes(w http.ResponseWriter, r *http.Request) {
+	var request ListScopesRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.scopeService.ListScopes(r.Context(), request)
+	if err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+func (s *scopeServiceServer) handlePutScope(w http.ResponseWriter, r *http.Request) {
+	var request PutScopeRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.scopeService.PutScope(r.Context(), request)
+	if err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+type securityServiceServer struct {
+	server          *otohttp.Server
+	securityService SecurityService
+}
+
+// Register adds the SecurityService to the otohttp.Server.
+func RegisterSecurityService(server *otohttp.Server, securityService SecurityService) {
+	handler := &securityServiceServer{
+		server:          server,
+		securityService: securityService,
+	}
+	server.Register("SecurityService", "ClearLockout", handler.handleClearLockout)
+	server.Register("SecurityService", "ListLockouts", handler.handleListLockouts)
+	server.Register("SecurityService", "ListViolations", handler.handleListViolations)
+}
+
+func (s *securityServiceServer) handleClearLockout(w http.ResponseWriter, r *http.Request) {
+	var request ClearLockoutRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.securityService.ClearLockout(r.Context(), request)
+	if err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+func (s *securityServiceServer) handleListLockouts(w http.ResponseWriter, r *http.Request) {
+	var request ListLockoutsRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.securityService.ListLockouts(r.Context(), request)
+	if err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+func (s *securityServiceServer) handleListViolations(w http.ResponseWriter, r *http.Request) {
+	var request ListViolationsRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.securityService.ListViolations(r.Context(), request)
+	if err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+type sessionServiceServer struct {
+	server         *otohttp.Server
+	sessionService SessionService
+}
+
+// Register adds the SessionService to the otohttp.Server.
+func RegisterSessionService(server *otohttp.Server, sessionService SessionService) {
+	handler := &sessionServiceServer{
+		server:         server,
+		sessionService: sessionService,
+	}
+	server.Register("SessionService", "GetSession", handler.handleGetSession)
+	server.Register("SessionService", "ListSessionsBySubject", handler.handleListSessionsBySubject)
+	server.Register("SessionService", "RevokeAllSessionsForSubject", handler.handleRevokeAllSessionsForSubject)
+	server.Register("SessionService", "RevokeSession", handler.handleRevokeSession)
+}
+
+func (s *sessionServiceServer) handleGetSession(w http.ResponseWriter, r *http.Request) {
+	var request GetSessionRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.sessionService.GetSession(r.Context(), request)
+	if err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+func (s *sessionServiceServer) handleListSessionsBySubject(w http.ResponseWriter, r *http.Request) {
+	var request ListSessionsBySubjectRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.sessionService.ListSessionsBySubject(r.Context(), request)
+	if err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+func (s *sessionServiceServer) handleRevokeAllSessionsForSubject(w http.ResponseWriter, r *http.Request) {
+	var request RevokeAllSessionsForSubjectRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.sessionService.RevokeAllSessionsForSubject(r.Context(), request)
+	if err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
+func (s *sessionServiceServer) handleRevokeSession(w http.ResponseWriter, r *http.Request) {
+	var request RevokeSessionRequest
+	if err := otohttp.Decode(r, &request); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	response, err := s.sessionService.RevokeSession(r.Context(), request)
+	if err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+	if err := otohttp.Encode(w, r, http.StatusOK, response); err != nil {
+		s.server.OnErr(w, r, err)
+		return
+	}
+}
+
 type AuthenticateRequest struct {
 	ChallengeID string `json:"challengeID"`
 	SubjectID   string `json:"subjectID"`
@@ -109,9 +572,85 @@ type AuthenticateResponse struct {
 	Error string `json:"error,omitempty"`
 }
 
+type ClearLockoutRequest struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+type ClearLockoutResponse struct {
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+type CompleteBackchannelAuthenticationRequest struct {
+	AuthReqID string `json:"authReqID"`
+	SubjectID string `json:"subjectID"`
+}
+
+type CompleteBackchannelAuthenticationResponse struct {
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+type Consent struct {
+	ClientID             string   `json:"clientID"`
+	SubjectID            string   `json:"subjectID"`
+	Scopes               []string `json:"scopes"`
+	AuthorizationDetails []string `json:"authorizationDetails"`
+	ExpiresAt            int64    `json:"expiresAt"`
+	CreatedAt            int64    `json:"createdAt"`
+	UpdatedAt            int64    `json:"updatedAt"`
+}
+
+type ConsentEvent struct {
+	Type                        string   `json:"type"`
+	ClientID                    string   `json:"clientID"`
+	SubjectID                   string   `json:"subjectID"`
+	AddedScopes                 []string `json:"addedScopes"`
+	RemovedScopes               []string `json:"removedScopes"`
+	AddedAuthorizationDetails   []string `json:"addedAuthorizationDetails"`
+	RemovedAuthorizationDetails []string `json:"removedAuthorizationDetails"`
+	ExpiresAt                   int64    `json:"expiresAt"`
+	ChallengeID                 string   `json:"challengeID"`
+	RequestID                   string   `json:"requestID"`
+	CreatedAt                   int64    `json:"createdAt"`
+}
+
+type DeleteScopeRequest struct {
+	Name string `json:"name"`
+}
+
+type DeleteScopeResponse struct {
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+type GetScopeRequest struct {
+	Name string `json:"name"`
+}
+
+type GetScopeResponse struct {
+	Scope Scope `json:"scope"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+type GetSessionRequest struct {
+	SessionID string `json:"sessionID"`
+}
+
+type GetSessionResponse struct {
+	Session Session `json:"session"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
 type GrantConsentRequest struct {
-	ChallengeID string   `json:"challengeID"`
-	Scopes      []string `json:"scopes"`
+	ChallengeID          string   `json:"challengeID"`
+	Scopes               []string `json:"scopes"`
+	AuthorizationDetails []string `json:"authorizationDetails"`
+	Remember             bool     `json:"remember"`
+	RememberFor          int64    `json:"rememberFor"`
 }
 
 type GrantConsentResponse struct {
@@ -120,15 +659,255 @@ type GrantConsentResponse struct {
 	Error string `json:"error,omitempty"`
 }
 
+type ListConsentHistoryRequest struct {
+	SubjectID string `json:"subjectID"`
+	ClientID  string `json:"clientID"`
+}
+
+type ListConsentHistoryResponse struct {
+	Events []ConsentEvent `json:"events"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+type ListConsentsBySubjectRequest struct {
+	SubjectID string `json:"subjectID"`
+}
+
+type ListConsentsBySubjectResponse struct {
+	Consents []Consent `json:"consents"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+type ListLockoutsRequest struct {
+}
+
+type ListLockoutsResponse struct {
+	Lockouts []Lockout `json:"lockouts"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+type ListScopesRequest struct {
+}
+
+type ListScopesResponse struct {
+	Scopes []Scope `json:"scopes"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+type ListSessionsBySubjectRequest struct {
+	SubjectID string `json:"subjectID"`
+}
+
+type ListSessionsBySubjectResponse struct {
+	Sessions []Session `json:"sessions"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+type ListViolationsRequest struct {
+	Since     int64  `json:"since"`
+	Type      string `json:"type"`
+	ClientID  string `json:"clientID"`
+	SubjectID string `json:"subjectID"`
+}
+
+type ListViolationsResponse struct {
+	Violations []Violation `json:"violations"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+type Lockout struct {
+	Kind       string `json:"kind"`
+	Value      string `json:"value"`
+	Violations int    `json:"violations"`
+	ExpiresAt  int64  `json:"expiresAt"`
+	CreatedAt  int64  `json:"createdAt"`
+}
+
+type PutScopeRequest struct {
+	Scope Scope `json:"scope"`
+}
+
+type PutScopeResponse struct {
+	Scope Scope `json:"scope"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+type RejectBackchannelAuthenticationRequest struct {
+	AuthReqID string `json:"authReqID"`
+}
+
+type RejectBackchannelAuthenticationResponse struct {
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+type RejectConsentRequest struct {
+	ChallengeID      string `json:"challengeID"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"errorDescription"`
+}
+
+type RejectConsentResponse struct {
+	RedirectURL string `json:"redirectURL"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+type RejectLoginRequest struct {
+	ChallengeID      string `json:"challengeID"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"errorDescription"`
+}
+
+type RejectLoginResponse struct {
+	RedirectURL string `json:"redirectURL"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+type RequestedScope struct {
+	Value      string           `json:"value"`
+	Parameters []ScopeParameter `json:"parameters"`
+	Scope      Scope            `json:"scope"`
+}
+
+type RevokeAllSessionsForSubjectRequest struct {
+	SubjectID    string `json:"subjectID"`
+	RevokeTokens bool   `json:"revokeTokens"`
+}
+
+type RevokeAllSessionsForSubjectResponse struct {
+	RevokedSessions int `json:"revokedSessions"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+type RevokeConsentRequest struct {
+	ClientID  string `json:"clientID"`
+	SubjectID string `json:"subjectID"`
+}
+
+type RevokeConsentResponse struct {
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+type RevokeScopesRequest struct {
+	ClientID  string   `json:"clientID"`
+	SubjectID string   `json:"subjectID"`
+	Scopes    []string `json:"scopes"`
+}
+
+type RevokeScopesResponse struct {
+	Consent Consent `json:"consent"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+type RevokeSessionRequest struct {
+	SessionID    string `json:"sessionID"`
+	RevokeTokens bool   `json:"revokeTokens"`
+}
+
+type RevokeSessionResponse struct {
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+type Scope struct {
+	Name            string             `json:"name"`
+	DisplayName     string             `json:"displayName"`
+	Descriptions    []ScopeDescription `json:"descriptions"`
+	RequiresConsent bool               `json:"requiresConsent"`
+	Sensitive       bool               `json:"sensitive"`
+	Implies         []string           `json:"implies"`
+}
+
+type ScopeDescription struct {
+	Locale      string `json:"locale"`
+	Description string `json:"description"`
+}
+
+type ScopeParameter struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type Session struct {
+	SessionID       string   `json:"sessionID"`
+	SubjectID       string   `json:"subjectID"`
+	ClientIDs       []string `json:"clientIDs"`
+	UserAgent       string   `json:"userAgent"`
+	IPAddress       string   `json:"ipAddress"`
+	AuthenticatedAt int64    `json:"authenticatedAt"`
+	ExpiresAt       int64    `json:"expiresAt"`
+	CreatedAt       int64    `json:"createdAt"`
+}
+
+type ShowBackchannelAuthenticationRequest struct {
+	AuthReqID string `json:"authReqID"`
+}
+
+type ShowBackchannelAuthenticationResponse struct {
+	ClientID        string   `json:"clientID"`
+	RequestedScopes []string `json:"requestedScopes"`
+	LoginHint       string   `json:"loginHint"`
+	BindingMessage  string   `json:"bindingMessage"`
+	ExpiresAt       int64    `json:"expiresAt"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
 type ShowConsentChallengeRequest struct {
 	ConsentChallenge string `json:"consentChallenge"`
 }
 
 type ShowConsentChallengeResponse struct {
-	ClientID        string   `json:"clientID"`
-	SubjectID       string   `json:"subjectID"`
-	RequestedScopes []string `json:"requestedScopes"`
-	MissingScopes   []string `json:"missingScopes"`
+	ClientID                      string           `json:"clientID"`
+	SubjectID                     string           `json:"subjectID"`
+	RequestedScopes               []string         `json:"requestedScopes"`
+	MissingScopes                 []string         `json:"missingScopes"`
+	RequestedAuthorizationDetails []string         `json:"requestedAuthorizationDetails"`
+	MissingAuthorizationDetails   []string         `json:"missingAuthorizationDetails"`
+	RequestedScopeMetadata        []RequestedScope `json:"requestedScopeMetadata"`
 	// Error is string explaining what went wrong. Empty if everything was fine.
 	Error string `json:"error,omitempty"`
 }
+
+type ShowLoginChallengeRequest struct {
+	LoginChallenge string `json:"loginChallenge"`
+}
+
+type ShowLoginChallengeResponse struct {
+	ClientID                      string   `json:"clientID"`
+	ClientDomain                  string   `json:"clientDomain"`
+	RequestedScopes               []string `json:"requestedScopes"`
+	RequestedAuthorizationDetails []string `json:"requestedAuthorizationDetails"`
+	LoginHint                     string   `json:"loginHint"`
+	UILocales                     []string `json:"uiLocales"`
+	Prompt                        string   `json:"prompt"`
+	SessionExists                 bool     `json:"sessionExists"`
+	// Error is string explaining what went wrong. Empty if everything was fine.
+	Error string `json:"error,omitempty"`
+}
+
+type Violation struct {
+	ViolationID string `json:"violationID"`
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	ChallengeID string `json:"challengeID"`
+	ClientID    string `json:"clientID"`
+	SubjectID   string `json:"subjectID"`
+	RequestID   string `json:"requestID"`
+	IPAddress   string `json:"ipAddress"`
+	UserAgent   string `json:"userAgent"`
+	Referer     string `json:"referer"`
+	CreatedAt   int64  `json:"createdAt"`
+}
@@ -0,0 +1,32 @@
+package admin
+
+type BackchannelService interface {
+	ShowBackchannelAuthentication(ShowBackchannelAuthenticationRequest) ShowBackchannelAuthenticationResponse
+	CompleteBackchannelAuthentication(CompleteBackchannelAuthenticationRequest) CompleteBackchannelAuthenticationResponse
+	RejectBackchannelAuthentication(RejectBackchannelAuthenticationRequest) RejectBackchannelAuthenticationResponse
+}
+
+type ShowBackchannelAuthenticationRequest struct {
+	AuthReqID string
+}
+
+type ShowBackchannelAuthenticationResponse struct {
+	ClientID        string
+	RequestedScopes []string
+	LoginHint       string
+	BindingMessage  string
+	ExpiresAt       int64
+}
+
+type CompleteBackchannelAuthenticationRequest struct {
+	AuthReqID string
+	SubjectID string
+}
+
+type CompleteBackchannelAuthenticationResponse struct{}
+
+type RejectBackchannelAuthenticationRequest struct {
+	AuthReqID string
+}
+
+type RejectBackchannelAuthenticationResponse struct{}
@@ -2,6 +2,8 @@ package admin
 
 type IdentityService interface {
 	Authenticate(AuthenticateRequest) AuthenticateResponse
+	RejectLogin(RejectLoginRequest) RejectLoginResponse
+	ShowLoginChallenge(ShowLoginChallengeRequest) ShowLoginChallengeResponse
 }
 
 type AuthenticateRequest struct {
@@ -13,9 +15,39 @@ type AuthenticateResponse struct {
 	RedirectURL string
 }
 
+type RejectLoginRequest struct {
+	ChallengeID      string
+	Error            string
+	ErrorDescription string
+}
+
+type RejectLoginResponse struct {
+	RedirectURL string
+}
+
+type ShowLoginChallengeRequest struct {
+	LoginChallenge string
+}
+
+type ShowLoginChallengeResponse struct {
+	ClientID                      string
+	ClientDomain                  string
+	RequestedScopes               []string
+	RequestedAuthorizationDetails []string
+	LoginHint                     string
+	UILocales                     []string
+	Prompt                        string
+	SessionExists                 bool
+}
+
 type ConsentService interface {
 	ShowConsentChallenge(ShowConsentChallengeRequest) ShowConsentChallengeResponse
 	GrantConsent(GrantConsentRequest) GrantConsentResponse
+	RejectConsent(RejectConsentRequest) RejectConsentResponse
+	ListConsentsBySubject(ListConsentsBySubjectRequest) ListConsentsBySubjectResponse
+	RevokeConsent(RevokeConsentRequest) RevokeConsentResponse
+	RevokeScopes(RevokeScopesRequest) RevokeScopesResponse
+	ListConsentHistory(ListConsentHistoryRequest) ListConsentHistoryResponse
 }
 
 type ShowConsentChallengeRequest struct {
@@ -23,17 +55,91 @@ type ShowConsentChallengeRequest struct {
 }
 
 type ShowConsentChallengeResponse struct {
-	ClientID        string
-	SubjectID       string
-	RequestedScopes []string
-	MissingScopes   []string
+	ClientID                      string
+	SubjectID                     string
+	RequestedScopes               []string
+	MissingScopes                 []string
+	RequestedAuthorizationDetails []string
+	MissingAuthorizationDetails   []string
+	RequestedScopeMetadata        []RequestedScope
 }
 
 type GrantConsentRequest struct {
-	ChallengeID string
-	Scopes      []string
+	ChallengeID          string
+	Scopes               []string
+	AuthorizationDetails []string
+	Remember             bool
+	RememberFor          int64
 }
 
 type GrantConsentResponse struct {
 	RedirectURL string
 }
+
+type RejectConsentRequest struct {
+	ChallengeID      string
+	Error            string
+	ErrorDescription string
+}
+
+type RejectConsentResponse struct {
+	RedirectURL string
+}
+
+type Consent struct {
+	ClientID             string
+	SubjectID            string
+	Scopes               []string
+	AuthorizationDetails []string
+	ExpiresAt            int64
+	CreatedAt            int64
+	UpdatedAt            int64
+}
+
+type ListConsentsBySubjectRequest struct {
+	SubjectID string
+}
+
+type ListConsentsBySubjectResponse struct {
+	Consents []Consent
+}
+
+type RevokeConsentRequest struct {
+	ClientID  string
+	SubjectID string
+}
+
+type RevokeConsentResponse struct{}
+
+type RevokeScopesRequest struct {
+	ClientID  string
+	SubjectID string
+	Scopes    []string
+}
+
+type RevokeScopesResponse struct {
+	Consent Consent
+}
+
+type ConsentEvent struct {
+	Type                        string
+	ClientID                    string
+	SubjectID                   string
+	AddedScopes                 []string
+	RemovedScopes               []string
+	AddedAuthorizationDetails   []string
+	RemovedAuthorizationDetails []string
+	ExpiresAt                   int64
+	ChallengeID                 string
+	RequestID                   string
+	CreatedAt                   int64
+}
+
+type ListConsentHistoryRequest struct {
+	SubjectID string
+	ClientID  string
+}
+
+type ListConsentHistoryResponse struct {
+	Events []ConsentEvent
+}
@@ -0,0 +1,61 @@
+package admin
+
+type ScopeService interface {
+	ListScopes(ListScopesRequest) ListScopesResponse
+	GetScope(GetScopeRequest) GetScopeResponse
+	PutScope(PutScopeRequest) PutScopeResponse
+	DeleteScope(DeleteScopeRequest) DeleteScopeResponse
+}
+
+type Scope struct {
+	Name            string
+	DisplayName     string
+	Descriptions    []ScopeDescription
+	RequiresConsent bool
+	Sensitive       bool
+	Implies         []string
+}
+
+type ScopeDescription struct {
+	Locale      string
+	Description string
+}
+
+type ListScopesRequest struct{}
+
+type ListScopesResponse struct {
+	Scopes []Scope
+}
+
+type GetScopeRequest struct {
+	Name string
+}
+
+type GetScopeResponse struct {
+	Scope Scope
+}
+
+type PutScopeRequest struct {
+	Scope Scope
+}
+
+type PutScopeResponse struct {
+	Scope Scope
+}
+
+type DeleteScopeRequest struct {
+	Name string
+}
+
+type DeleteScopeResponse struct{}
+
+type RequestedScope struct {
+	Value      string
+	Parameters []ScopeParameter
+	Scope      Scope
+}
+
+type ScopeParameter struct {
+	Name  string
+	Value string
+}
@@ -0,0 +1,56 @@
+package admin
+
+type SecurityService interface {
+	ListViolations(ListViolationsRequest) ListViolationsResponse
+	ListLockouts(ListLockoutsRequest) ListLockoutsResponse
+	ClearLockout(ClearLockoutRequest) ClearLockoutResponse
+}
+
+type Violation struct {
+	ViolationID string
+	Type        string
+	Description string
+	ChallengeID string
+	ClientID    string
+	SubjectID   string
+	RequestID   string
+	IPAddress   string
+	UserAgent   string
+	Referer     string
+	CreatedAt   int64
+}
+
+// ListViolationsRequest lists integrity violations recorded since given unix time. Empty filters match any value.
+type ListViolationsRequest struct {
+	Since     int64
+	Type      string
+	ClientID  string
+	SubjectID string
+}
+
+type ListViolationsResponse struct {
+	Violations []Violation
+}
+
+// Lockout blocks authorization requests of subject, client or source IP address until it expires.
+// Kind is one of "subject", "client" or "ip_address".
+type Lockout struct {
+	Kind       string
+	Value      string
+	Violations int
+	ExpiresAt  int64
+	CreatedAt  int64
+}
+
+type ListLockoutsRequest struct{}
+
+type ListLockoutsResponse struct {
+	Lockouts []Lockout
+}
+
+type ClearLockoutRequest struct {
+	Kind  string
+	Value string
+}
+
+type ClearLockoutResponse struct{}
@@ -0,0 +1,51 @@
+package admin
+
+type SessionService interface {
+	ListSessionsBySubject(ListSessionsBySubjectRequest) ListSessionsBySubjectResponse
+	GetSession(GetSessionRequest) GetSessionResponse
+	RevokeSession(RevokeSessionRequest) RevokeSessionResponse
+	RevokeAllSessionsForSubject(RevokeAllSessionsForSubjectRequest) RevokeAllSessionsForSubjectResponse
+}
+
+type Session struct {
+	SessionID       string
+	SubjectID       string
+	ClientIDs       []string
+	UserAgent       string
+	IPAddress       string
+	AuthenticatedAt int64
+	ExpiresAt       int64
+	CreatedAt       int64
+}
+
+type ListSessionsBySubjectRequest struct {
+	SubjectID string
+}
+
+type ListSessionsBySubjectResponse struct {
+	Sessions []Session
+}
+
+type GetSessionRequest struct {
+	SessionID string
+}
+
+type GetSessionResponse struct {
+	Session Session
+}
+
+type RevokeSessionRequest struct {
+	SessionID    string
+	RevokeTokens bool
+}
+
+type RevokeSessionResponse struct{}
+
+type RevokeAllSessionsForSubjectRequest struct {
+	SubjectID    string
+	RevokeTokens bool
+}
+
+type RevokeAllSessionsForSubjectResponse struct {
+	RevokedSessions int
+}
@@ -42,6 +42,185 @@ func New(remoteHost string) *Client {
 	return c
 }
 
+type BackchannelService struct {
+	client *Client
+}
+
+// NewBackchannelService makes a new client for accessing BackchannelService services.
+func NewBackchannelService(client *Client) *BackchannelService {
+	return &BackchannelService{
+		client: client,
+	}
+}
+
+func (s *BackchannelService) CompleteBackchannelAuthentication(ctx context.Context, r CompleteBackchannelAuthenticationRequest) (*CompleteBackchannelAuthenticationResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "BackchannelService.CompleteBackchannelAuthentication: marshal CompleteBackchannelAuthenticationRequest")
+	}
+	url := s.client.RemoteHost + "BackchannelService.CompleteBackchannelAuthentication"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "BackchannelService.CompleteBackchannelAuthentication: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req = req.WithContext(ctx)
+	if s.client.BeforeRequest != nil {
+		err = s.client.BeforeRequest(req)
+		if err != nil {
+			// don't wrap this error, it belongs to the user
+			return nil, err
+		}
+	}
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "BackchannelService.CompleteBackchannelAuthentication")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		CompleteBackchannelAuthenticationResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "BackchannelService.CompleteBackchannelAuthentication: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "BackchannelService.CompleteBackchannelAuthentication: read response body")
+	}
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("BackchannelService.CompleteBackchannelAuthentication: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.CompleteBackchannelAuthenticationResponse, nil
+}
+
+func (s *BackchannelService) RejectBackchannelAuthentication(ctx context.Context, r RejectBackchannelAuthenticationRequest) (*RejectBackchannelAuthenticationResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "BackchannelService.RejectBackchannelAuthentication: marshal RejectBackchannelAuthenticationRequest")
+	}
+	url := s.client.RemoteHost + "BackchannelService.RejectBackchannelAuthentication"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "BackchannelService.RejectBackchannelAuthentication: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req = req.WithContext(ctx)
+	if s.client.BeforeRequest != nil {
+		err = s.client.BeforeRequest(req)
+		if err != nil {
+			// don't wrap this error, it belongs to the user
+			return nil, err
+		}
+	}
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "BackchannelService.RejectBackchannelAuthentication")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		RejectBackchannelAuthenticationResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "BackchannelService.RejectBackchannelAuthentication: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "BackchannelService.RejectBackchannelAuthentication: read response body")
+	}
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("BackchannelService.RejectBackchannelAuthentication: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.RejectBackchannelAuthenticationResponse, nil
+}
+
+func (s *BackchannelService) ShowBackchannelAuthentication(ctx context.Context, r ShowBackchannelAuthenticationRequest) (*ShowBackchannelAuthenticationResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "BackchannelService.ShowBackchannelAuthentication: marshal ShowBackchannelAuthenticationRequest")
+	}
+	url := s.client.RemoteHost + "BackchannelService.ShowBackchannelAuthentication"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "BackchannelService.ShowBackchannelAuthentication: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req = req.WithContext(ctx)
+	if s.client.BeforeRequest != nil {
+		err = s.client.BeforeRequest(req)
+		if err != nil {
+			// don't wrap this error, it belongs to the user
+			return nil, err
+		}
+	}
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "BackchannelService.ShowBackchannelAuthentication")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		ShowBackchannelAuthenticationResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "BackchannelService.ShowBackchannelAuthentication: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "BackchannelService.ShowBackchannelAuthentication: read response body")
+	}
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("BackchannelService.ShowBackchannelAuthentication: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.ShowBackchannelAuthenticationResponse, nil
+}
+
 type ConsentService struct {
 	client *Client
 }
@@ -53,17 +232,1069 @@ func NewConsentService(client *Client) *ConsentService {
 	}
 }
 
-func (s *ConsentService) GrantConsent(ctx context.Context, r GrantConsentRequest) (*GrantConsentResponse, error) {
+func (s *ConsentService) GrantConsent(ctx context.Context, r GrantConsentRequest) (*GrantConsentResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "ConsentService.GrantConsent: marshal GrantConsentRequest")
+	}
+	url := s.client.RemoteHost + "ConsentService.GrantConsent"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "ConsentService.GrantConsent: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req = req.WithContext(ctx)
+	if s.client.BeforeRequest != nil {
+		err = s.client.BeforeRequest(req)
+		if err != nil {
+			// don't wrap this error, it belongs to the user
+			return nil, err
+		}
+	}
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "ConsentService.GrantConsent")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		GrantConsentResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "ConsentService.GrantConsent: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "ConsentService.GrantConsent: read response body")
+	}
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("ConsentService.GrantConsent: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.GrantConsentResponse, nil
+}
+
+func (s *ConsentService) ListConsentHistory(ctx context.Context, r ListConsentHistoryRequest) (*ListConsentHistoryResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "ConsentService.ListConsentHistory: marshal ListConsentHistoryRequest")
+	}
+	url := s.client.RemoteHost + "ConsentService.ListConsentHistory"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "ConsentService.ListConsentHistory: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req = req.WithContext(ctx)
+	if s.client.BeforeRequest != nil {
+		err = s.client.BeforeRequest(req)
+		if err != nil {
+			// don't wrap this error, it belongs to the user
+			return nil, err
+		}
+	}
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "ConsentService.ListConsentHistory")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		ListConsentHistoryResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "ConsentService.ListConsentHistory: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "ConsentService.ListConsentHistory: read response body")
+	}
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("ConsentService.ListConsentHistory: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.ListConsentHistoryResponse, nil
+}
+
+func (s *ConsentService) ListConsentsBySubject(ctx context.Context, r ListConsentsBySubjectRequest) (*ListConsentsBySubjectResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "ConsentService.ListConsentsBySubject: marshal ListConsentsBySubjectRequest")
+	}
+	url := s.client.RemoteHost + "ConsentService.ListConsentsBySubject"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "ConsentService.ListConsentsBySubject: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req = req.WithContext(ctx)
+	if s.client.BeforeRequest != nil {
+		err = s.client.BeforeRequest(req)
+		if err != nil {
+			// don't wrap this error, it belongs to the user
+			return nil, err
+		}
+	}
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "ConsentService.ListConsentsBySubject")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		ListConsentsBySubjectResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "ConsentService.ListConsentsBySubject: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "ConsentService.ListConsentsBySubject: read response body")
+	}
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("ConsentService.ListConsentsBySubject: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.ListConsentsBySubjectResponse, nil
+}
+
+func (s *ConsentService) RejectConsent(ctx context.Context, r RejectConsentRequest) (*RejectConsentResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "ConsentService.RejectConsent: marshal RejectConsentRequest")
+	}
+	url := s.client.RemoteHost + "ConsentService.RejectConsent"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "ConsentService.RejectConsent: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req = req.WithContext(ctx)
+	if s.client.BeforeRequest != nil {
+		err = s.client.BeforeRequest(req)
+		if err != nil {
+			// don't wrap this error, it belongs to the user
+			return nil, err
+		}
+	}
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "ConsentService.RejectConsent")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		RejectConsentResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "ConsentService.RejectConsent: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "ConsentService.RejectConsent: read response body")
+	}
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("ConsentService.RejectConsent: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.RejectConsentResponse, nil
+}
+
+func (s *ConsentService) RevokeConsent(ctx context.Context, r RevokeConsentRequest) (*RevokeConsentResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "ConsentService.RevokeConsent: marshal RevokeConsentRequest")
+	}
+	url := s.client.RemoteHost + "ConsentService.RevokeConsent"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "ConsentService.RevokeConsent: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req = req.WithContext(ctx)
+	if s.client.BeforeRequest != nil {
+		err = s.client.BeforeRequest(req)
+		if err != nil {
+			// don't wrap this error, it belongs to the user
+			return nil, err
+		}
+	}
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "ConsentService.RevokeConsent")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		RevokeConsentResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "ConsentService.RevokeConsent: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "ConsentService.RevokeConsent: read response body")
+	}
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("ConsentService.RevokeConsent: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.RevokeConsentResponse, nil
+}
+
+func (s *ConsentService) RevokeScopes(ctx context.Context, r RevokeScopesRequest) (*RevokeScopesResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "ConsentService.RevokeScopes: marshal RevokeScopesRequest")
+	}
+	url := s.client.RemoteHost + "ConsentService.RevokeScopes"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "ConsentService.RevokeScopes: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req = req.WithContext(ctx)
+	if s.client.BeforeRequest != nil {
+		err = s.client.BeforeRequest(req)
+		if err != nil {
+			// don't wrap this error, it belongs to the user
+			return nil, err
+		}
+	}
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "ConsentService.RevokeScopes")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		RevokeScopesResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "ConsentService.RevokeScopes: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "ConsentService.RevokeScopes: read response body")
+	}
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("ConsentService.RevokeScopes: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.RevokeScopesResponse, nil
+}
+
+func (s *ConsentService) ShowConsentChallenge(ctx context.Context, r ShowConsentChallengeRequest) (*ShowConsentChallengeResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "ConsentService.ShowConsentChallenge: marshal ShowConsentChallengeRequest")
+	}
+	url := s.client.RemoteHost + "ConsentService.ShowConsentChallenge"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "ConsentService.ShowConsentChallenge: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req = req.WithContext(ctx)
+	if s.client.BeforeRequest != nil {
+		err = s.client.BeforeRequest(req)
+		if err != nil {
+			// don't wrap this error, it belongs to the user
+			return nil, err
+		}
+	}
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "ConsentService.ShowConsentChallenge")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		ShowConsentChallengeResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "ConsentService.ShowConsentChallenge: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "ConsentService.ShowConsentChallenge: read response body")
+	}
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("ConsentService.ShowConsentChallenge: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.ShowConsentChallengeResponse, nil
+}
+
+type IdentityService struct {
+	client *Client
+}
+
+// NewIdentityService makes a new client for accessing IdentityService services.
+func NewIdentityService(client *Client) *IdentityService {
+	return &IdentityService{
+		client: client,
+	}
+}
+
+func (s *IdentityService) Authenticate(ctx context.Context, r AuthenticateRequest) (*AuthenticateResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "IdentityService.Authenticate: marshal AuthenticateRequest")
+	}
+	url := s.client.RemoteHost + "IdentityService.Authenticate"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "IdentityService.Authenticate: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req = req.WithContext(ctx)
+	if s.client.BeforeRequest != nil {
+		err = s.client.BeforeRequest(req)
+		if err != nil {
+			// don't wrap this error, it belongs to the user
+			return nil, err
+		}
+	}
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "IdentityService.Authenticate")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		AuthenticateResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "IdentityService.Authenticate: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "IdentityService.Authenticate: read response body")
+	}
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("IdentityService.Authenticate: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.AuthenticateResponse, nil
+}
+
+func (s *IdentityService) RejectLogin(ctx context.Context, r RejectLoginRequest) (*RejectLoginResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "IdentityService.RejectLogin: marshal RejectLoginRequest")
+	}
+	url := s.client.RemoteHost + "IdentityService.RejectLogin"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "IdentityService.RejectLogin: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req = req.WithContext(ctx)
+	if s.client.BeforeRequest != nil {
+		err = s.client.BeforeRequest(req)
+		if err != nil {
+			// don't wrap this error, it belongs to the user
+			return nil, err
+		}
+	}
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "IdentityService.RejectLogin")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		RejectLoginResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "IdentityService.RejectLogin: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "IdentityService.RejectLogin: read response body")
+	}
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("IdentityService.RejectLogin: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.RejectLoginResponse, nil
+}
+
+func (s *IdentityService) ShowLoginChallenge(ctx context.Context, r ShowLoginChallengeRequest) (*ShowLoginChallengeResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "IdentityService.ShowLoginChallenge: marshal ShowLoginChallengeRequest")
+	}
+	url := s.client.RemoteHost + "IdentityService.ShowLoginChallenge"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "IdentityService.ShowLoginChallenge: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req = req.WithContext(ctx)
+	if s.client.BeforeRequest != nil {
+		err = s.client.BeforeRequest(req)
+		if err != nil {
+			// don't wrap this error, it belongs to the user
+			return nil, err
+		}
+	}
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "IdentityService.ShowLoginChallenge")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		ShowLoginChallengeResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "IdentityService.ShowLoginChallenge: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "IdentityService.ShowLoginChallenge: read response body")
+	}
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("IdentityService.ShowLoginChallenge: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.ShowLoginChallengeResponse, nil
+}
+
+type ScopeService struct {
+	client *Client
+}
+
+// NewScopeService makes a new client for accessing ScopeService services.
+func NewScopeService(client *Client) *ScopeService {
+	return &ScopeService{
+		client: client,
+	}
+}
+
+func (s *ScopeService) DeleteScope(ctx context.Context, r DeleteScopeRequest) (*DeleteScopeResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "ScopeService.DeleteScope: marshal DeleteScopeRequest")
+	}
+	url := s.client.RemoteHost + "ScopeService.DeleteScope"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "ScopeService.DeleteScope: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req = req.WithContext(ctx)
+	if s.client.BeforeRequest != nil {
+		err = s.client.BeforeRequest(req)
+		if err != nil {
+			// don't wrap this error, it belongs to the user
+			return nil, err
+		}
+	}
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "ScopeService.DeleteScope")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		DeleteScopeResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "ScopeService.DeleteScope: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "ScopeService.DeleteScope: read response body")
+	}
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("ScopeService.DeleteScope: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.DeleteScopeResponse, nil
+}
+
+func (s *ScopeService) GetScope(ctx context.Context, r GetScopeRequest) (*GetScopeResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "ScopeService.GetScope: marshal GetScopeRequest")
+	}
+	url := s.client.RemoteHost + "ScopeService.GetScope"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "ScopeService.GetScope: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req = req.WithContext(ctx)
+	if s.client.BeforeRequest != nil {
+		err = s.client.BeforeRequest(req)
+		if err != nil {
+			// don't wrap this error, it belongs to the user
+			return nil, err
+		}
+	}
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "ScopeService.GetScope")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		GetScopeResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "ScopeService.GetScope: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "ScopeService.GetScope: read response body")
+	}
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("ScopeService.GetScope: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.GetScopeResponse, nil
+}
+
+func (s *ScopeService) ListScopes(ctx context.Context, r ListScopesRequest) (*ListScopesResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "ScopeService.ListScopes: marshal ListScopesRequest")
+	}
+	url := s.client.RemoteHost + "ScopeService.ListScopes"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "ScopeService.ListScopes: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req = req.WithContext(ctx)
+	if s.client.BeforeRequest != nil {
+		err = s.client.BeforeRequest(req)
+		if err != nil {
+			// don't wrap this error, it belongs to the user
+			return nil, err
+		}
+	}
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "ScopeService.ListScopes")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		ListScopesResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "ScopeService.ListScopes: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "ScopeService.ListScopes: read response body")
+	}
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("ScopeService.ListScopes: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.ListScopesResponse, nil
+}
+
+func (s *ScopeService) PutScope(ctx context.Context, r PutScopeRequest) (*PutScopeResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "ScopeService.PutScope: marshal PutScopeRequest")
+	}
+	url := s.client.RemoteHost + "ScopeService.PutScope"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "ScopeService.PutScope: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req = req.WithContext(ctx)
+	if s.client.BeforeRequest != nil {
+		err = s.client.BeforeRequest(req)
+		if err != nil {
+			// don't wrap this error, it belongs to the user
+			return nil, err
+		}
+	}
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "ScopeService.PutScope")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		PutScopeResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "ScopeService.PutScope: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "ScopeService.PutScope: read response body")
+	}
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("ScopeService.PutScope: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.PutScopeResponse, nil
+}
+
+type SecurityService struct {
+	client *Client
+}
+
+// NewSecurityService makes a new client for accessing SecurityService services.
+func NewSecurityService(client *Client) *SecurityService {
+	return &SecurityService{
+		client: client,
+	}
+}
+
+func (s *SecurityService) ClearLockout(ctx context.Context, r ClearLockoutRequest) (*ClearLockoutResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "SecurityService.ClearLockout: marshal ClearLockoutRequest")
+	}
+	url := s.client.RemoteHost + "SecurityService.ClearLockout"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "SecurityService.ClearLockout: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req = req.WithContext(ctx)
+	if s.client.BeforeRequest != nil {
+		err = s.client.BeforeRequest(req)
+		if err != nil {
+			// don't wrap this error, it belongs to the user
+			return nil, err
+		}
+	}
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "SecurityService.ClearLockout")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		ClearLockoutResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "SecurityService.ClearLockout: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "SecurityService.ClearLockout: read response body")
+	}
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("SecurityService.ClearLockout: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.ClearLockoutResponse, nil
+}
+
+func (s *SecurityService) ListLockouts(ctx context.Context, r ListLockoutsRequest) (*ListLockoutsResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "SecurityService.ListLockouts: marshal ListLockoutsRequest")
+	}
+	url := s.client.RemoteHost + "SecurityService.ListLockouts"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "SecurityService.ListLockouts: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req = req.WithContext(ctx)
+	if s.client.BeforeRequest != nil {
+		err = s.client.BeforeRequest(req)
+		if err != nil {
+			// don't wrap this error, it belongs to the user
+			return nil, err
+		}
+	}
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "SecurityService.ListLockouts")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		ListLockoutsResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "SecurityService.ListLockouts: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "SecurityService.ListLockouts: read response body")
+	}
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("SecurityService.ListLockouts: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.ListLockoutsResponse, nil
+}
+
+func (s *SecurityService) ListViolations(ctx context.Context, r ListViolationsRequest) (*ListViolationsResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "SecurityService.ListViolations: marshal ListViolationsRequest")
+	}
+	url := s.client.RemoteHost + "SecurityService.ListViolations"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "SecurityService.ListViolations: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req = req.WithContext(ctx)
+	if s.client.BeforeRequest != nil {
+		err = s.client.BeforeRequest(req)
+		if err != nil {
+			// don't wrap this error, it belongs to the user
+			return nil, err
+		}
+	}
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "SecurityService.ListViolations")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		ListViolationsResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "SecurityService.ListViolations: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "SecurityService.ListViolations: read response body")
+	}
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("SecurityService.ListViolations: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.ListViolationsResponse, nil
+}
+
+type SessionService struct {
+	client *Client
+}
+
+// NewSessionService makes a new client for accessing SessionService services.
+func NewSessionService(client *Client) *SessionService {
+	return &SessionService{
+		client: client,
+	}
+}
+
+func (s *SessionService) GetSession(ctx context.Context, r GetSessionRequest) (*GetSessionResponse, error) {
+	requestBodyBytes, err := json.Marshal(r)
+	if err != nil {
+		return nil, errors.Wrap(err, "SessionService.GetSession: marshal GetSessionRequest")
+	}
+	url := s.client.RemoteHost + "SessionService.GetSession"
+	s.client.Debug(fmt.Sprintf("POST %s", url))
+	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
+	if err != nil {
+		return nil, errors.Wrap(err, "SessionService.GetSession: NewRequest")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Encoding", "gzip")
+	req = req.WithContext(ctx)
+	if s.client.BeforeRequest != nil {
+		err = s.client.BeforeRequest(req)
+		if err != nil {
+			// don't wrap this error, it belongs to the user
+			return nil, err
+		}
+	}
+	resp, err := s.client.HTTPClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "SessionService.GetSession")
+	}
+	defer resp.Body.Close()
+	var response struct {
+		GetSessionResponse
+		Error string
+	}
+	var bodyReader io.Reader = resp.Body
+	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
+		decodedBody, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, errors.Wrap(err, "SessionService.GetSession: new gzip reader")
+		}
+		defer decodedBody.Close()
+		bodyReader = decodedBody
+	}
+	respBodyBytes, err := ioutil.ReadAll(bodyReader)
+	if err != nil {
+		return nil, errors.Wrap(err, "SessionService.GetSession: read response body")
+	}
+	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, errors.Errorf("SessionService.GetSession: (%d) %v", resp.StatusCode, string(respBodyBytes))
+		}
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return &response.GetSessionResponse, nil
+}
+
+func (s *SessionService) ListSessionsBySubject(ctx context.Context, r ListSessionsBySubjectRequest) (*ListSessionsBySubjectResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "ConsentService.GrantConsent: marshal GrantConsentRequest")
+		return nil, errors.Wrap(err, "SessionService.ListSessionsBySubject: marshal ListSessionsBySubjectRequest")
 	}
-	url := s.client.RemoteHost + "ConsentService.GrantConsent"
+	url := s.client.RemoteHost + "SessionService.ListSessionsBySubject"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "ConsentService.GrantConsent: NewRequest")
+		return nil, errors.Wrap(err, "SessionService.ListSessionsBySubject: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -77,49 +1308,49 @@ func (s *ConsentService) GrantConsent(ctx context.Context, r GrantConsentRequest
 	}
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "ConsentService.GrantConsent")
+		return nil, errors.Wrap(err, "SessionService.ListSessionsBySubject")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		GrantConsentResponse
+		ListSessionsBySubjectResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "ConsentService.GrantConsent: new gzip reader")
+			return nil, errors.Wrap(err, "SessionService.ListSessionsBySubject: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "ConsentService.GrantConsent: read response body")
+		return nil, errors.Wrap(err, "SessionService.ListSessionsBySubject: read response body")
 	}
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("ConsentService.GrantConsent: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("SessionService.ListSessionsBySubject: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.GrantConsentResponse, nil
+	return &response.ListSessionsBySubjectResponse, nil
 }
 
-func (s *ConsentService) ShowConsentChallenge(ctx context.Context, r ShowConsentChallengeRequest) (*ShowConsentChallengeResponse, error) {
+func (s *SessionService) RevokeAllSessionsForSubject(ctx context.Context, r RevokeAllSessionsForSubjectRequest) (*RevokeAllSessionsForSubjectResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "ConsentService.ShowConsentChallenge: marshal ShowConsentChallengeRequest")
+		return nil, errors.Wrap(err, "SessionService.RevokeAllSessionsForSubject: marshal RevokeAllSessionsForSubjectRequest")
 	}
-	url := s.client.RemoteHost + "ConsentService.ShowConsentChallenge"
+	url := s.client.RemoteHost + "SessionService.RevokeAllSessionsForSubject"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "ConsentService.ShowConsentChallenge: NewRequest")
+		return nil, errors.Wrap(err, "SessionService.RevokeAllSessionsForSubject: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -133,60 +1364,49 @@ func (s *ConsentService) ShowConsentChallenge(ctx context.Context, r ShowConsent
 	}
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "ConsentService.ShowConsentChallenge")
+		return nil, errors.Wrap(err, "SessionService.RevokeAllSessionsForSubject")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		ShowConsentChallengeResponse
+		RevokeAllSessionsForSubjectResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "ConsentService.ShowConsentChallenge: new gzip reader")
+			return nil, errors.Wrap(err, "SessionService.RevokeAllSessionsForSubject: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "ConsentService.ShowConsentChallenge: read response body")
+		return nil, errors.Wrap(err, "SessionService.RevokeAllSessionsForSubject: read response body")
 	}
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("ConsentService.ShowConsentChallenge: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("SessionService.RevokeAllSessionsForSubject: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.ShowConsentChallengeResponse, nil
-}
-
-type IdentityService struct {
-	client *Client
-}
-
-// NewIdentityService makes a new client for accessing IdentityService services.
-func NewIdentityService(client *Client) *IdentityService {
-	return &IdentityService{
-		client: client,
-	}
+	return &response.RevokeAllSessionsForSubjectResponse, nil
 }
 
-func (s *IdentityService) Authenticate(ctx context.Context, r AuthenticateRequest) (*AuthenticateResponse, error) {
+func (s *SessionService) RevokeSession(ctx context.Context, r RevokeSessionRequest) (*RevokeSessionResponse, error) {
 	requestBodyBytes, err := json.Marshal(r)
 	if err != nil {
-		return nil, errors.Wrap(err, "IdentityService.Authenticate: marshal AuthenticateRequest")
+		return nil, errors.Wrap(err, "SessionService.RevokeSession: marshal RevokeSessionRequest")
 	}
-	url := s.client.RemoteHost + "IdentityService.Authenticate"
+	url := s.client.RemoteHost + "SessionService.RevokeSession"
 	s.client.Debug(fmt.Sprintf("POST %s", url))
 	s.client.Debug(fmt.Sprintf(">> %s", string(requestBodyBytes)))
 	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(requestBodyBytes))
 	if err != nil {
-		return nil, errors.Wrap(err, "IdentityService.Authenticate: NewRequest")
+		return nil, errors.Wrap(err, "SessionService.RevokeSession: NewRequest")
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept-Encoding", "gzip")
@@ -200,36 +1420,36 @@ func (s *IdentityService) Authenticate(ctx context.Context, r AuthenticateReques
 	}
 	resp, err := s.client.HTTPClient.Do(req)
 	if err != nil {
-		return nil, errors.Wrap(err, "IdentityService.Authenticate")
+		return nil, errors.Wrap(err, "SessionService.RevokeSession")
 	}
 	defer resp.Body.Close()
 	var response struct {
-		AuthenticateResponse
+		RevokeSessionResponse
 		Error string
 	}
 	var bodyReader io.Reader = resp.Body
 	if strings.Contains(resp.Header.Get("Content-Encoding"), "gzip") {
 		decodedBody, err := gzip.NewReader(resp.Body)
 		if err != nil {
-			return nil, errors.Wrap(err, "IdentityService.Authenticate: new gzip reader")
+			return nil, errors.Wrap(err, "SessionService.RevokeSession: new gzip reader")
 		}
 		defer decodedBody.Close()
 		bodyReader = decodedBody
 	}
 	respBodyBytes, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
-		return nil, errors.Wrap(err, "IdentityService.Authenticate: read response body")
+		return nil, errors.Wrap(err, "SessionService.RevokeSession: read response body")
 	}
 	if err := json.Unmarshal(respBodyBytes, &response); err != nil {
 		if resp.StatusCode != http.StatusOK {
-			return nil, errors.Errorf("IdentityService.Authenticate: (%d) %v", resp.StatusCode, string(respBodyBytes))
+			return nil, errors.Errorf("SessionService.RevokeSession: (%d) %v", resp.StatusCode, string(respBodyBytes))
 		}
 		return nil, err
 	}
 	if response.Error != "" {
 		return nil, errors.New(response.Error)
 	}
-	return &response.AuthenticateResponse, nil
+	return &response.RevokeSessionResponse, nil
 }
 
 type AuthenticateRequest struct {
@@ -242,16 +1462,316 @@ type AuthenticateResponse struct {
 	RedirectURL string `json:"redirectURL"`
 }
 
+type ClearLockoutRequest struct {
+	Kind string `json:"kind"`
+
+	Value string `json:"value"`
+}
+
+type ClearLockoutResponse struct {
+}
+
+type CompleteBackchannelAuthenticationRequest struct {
+	AuthReqID string `json:"authReqID"`
+
+	SubjectID string `json:"subjectID"`
+}
+
+type CompleteBackchannelAuthenticationResponse struct {
+}
+
+type Consent struct {
+	ClientID string `json:"clientID"`
+
+	SubjectID string `json:"subjectID"`
+
+	Scopes []string `json:"scopes"`
+
+	AuthorizationDetails []string `json:"authorizationDetails"`
+
+	ExpiresAt int64 `json:"expiresAt"`
+
+	CreatedAt int64 `json:"createdAt"`
+
+	UpdatedAt int64 `json:"updatedAt"`
+}
+
+type ConsentEvent struct {
+	Type string `json:"type"`
+
+	ClientID string `json:"clientID"`
+
+	SubjectID string `json:"subjectID"`
+
+	AddedScopes []string `json:"addedScopes"`
+
+	RemovedScopes []string `json:"removedScopes"`
+
+	AddedAuthorizationDetails []string `json:"addedAuthorizationDetails"`
+
+	RemovedAuthorizationDetails []string `json:"removedAuthorizationDetails"`
+
+	ExpiresAt int64 `json:"expiresAt"`
+
+	ChallengeID string `json:"challengeID"`
+
+	RequestID string `json:"requestID"`
+
+	CreatedAt int64 `json:"createdAt"`
+}
+
+type DeleteScopeRequest struct {
+	Name string `json:"name"`
+}
+
+type DeleteScopeResponse struct {
+}
+
+type GetScopeRequest struct {
+	Name string `json:"name"`
+}
+
+type GetScopeResponse struct {
+	Scope Scope `json:"scope"`
+}
+
+type GetSessionRequest struct {
+	SessionID string `json:"sessionID"`
+}
+
+type GetSessionResponse struct {
+	Session Session `json:"session"`
+}
+
 type GrantConsentRequest struct {
 	ChallengeID string `json:"challengeID"`
 
 	Scopes []string `json:"scopes"`
+
+	AuthorizationDetails []string `json:"authorizationDetails"`
+
+	Remember bool `json:"remember"`
+
+	RememberFor int64 `json:"rememberFor"`
 }
 
 type GrantConsentResponse struct {
 	RedirectURL string `json:"redirectURL"`
 }
 
+type ListConsentHistoryRequest struct {
+	SubjectID string `json:"subjectID"`
+
+	ClientID string `json:"clientID"`
+}
+
+type ListConsentHistoryResponse struct {
+	Events []ConsentEvent `json:"events"`
+}
+
+type ListConsentsBySubjectRequest struct {
+	SubjectID string `json:"subjectID"`
+}
+
+type ListConsentsBySubjectResponse struct {
+	Consents []Consent `json:"consents"`
+}
+
+type ListLockoutsRequest struct {
+}
+
+type ListLockoutsResponse struct {
+	Lockouts []Lockout `json:"lockouts"`
+}
+
+type ListScopesRequest struct {
+}
+
+type ListScopesResponse struct {
+	Scopes []Scope `json:"scopes"`
+}
+
+type ListSessionsBySubjectRequest struct {
+	SubjectID string `json:"subjectID"`
+}
+
+type ListSessionsBySubjectResponse struct {
+	Sessions []Session `json:"sessions"`
+}
+
+type ListViolationsRequest struct {
+	Since int64 `json:"since"`
+
+	Type string `json:"type"`
+
+	ClientID string `json:"clientID"`
+
+	SubjectID string `json:"subjectID"`
+}
+
+type ListViolationsResponse struct {
+	Violations []Violation `json:"violations"`
+}
+
+type Lockout struct {
+	Kind string `json:"kind"`
+
+	Value string `json:"value"`
+
+	Violations int `json:"violations"`
+
+	ExpiresAt int64 `json:"expiresAt"`
+
+	CreatedAt int64 `json:"createdAt"`
+}
+
+type PutScopeRequest struct {
+	Scope Scope `json:"scope"`
+}
+
+type PutScopeResponse struct {
+	Scope Scope `json:"scope"`
+}
+
+type RejectBackchannelAuthenticationRequest struct {
+	AuthReqID string `json:"authReqID"`
+}
+
+type RejectBackchannelAuthenticationResponse struct {
+}
+
+type RejectConsentRequest struct {
+	ChallengeID string `json:"challengeID"`
+
+	Error string `json:"error"`
+
+	ErrorDescription string `json:"errorDescription"`
+}
+
+type RejectConsentResponse struct {
+	RedirectURL string `json:"redirectURL"`
+}
+
+type RejectLoginRequest struct {
+	ChallengeID string `json:"challengeID"`
+
+	Error string `json:"error"`
+
+	ErrorDescription string `json:"errorDescription"`
+}
+
+type RejectLoginResponse struct {
+	RedirectURL string `json:"redirectURL"`
+}
+
+type RequestedScope struct {
+	Value string `json:"value"`
+
+	Parameters []ScopeParameter `json:"parameters"`
+
+	Scope Scope `json:"scope"`
+}
+
+type RevokeAllSessionsForSubjectRequest struct {
+	SubjectID string `json:"subjectID"`
+
+	RevokeTokens bool `json:"revokeTokens"`
+}
+
+type RevokeAllSessionsForSubjectResponse struct {
+	RevokedSessions int `json:"revokedSessions"`
+}
+
+type RevokeConsentRequest struct {
+	ClientID string `json:"clientID"`
+
+	SubjectID string `json:"subjectID"`
+}
+
+type RevokeConsentResponse struct {
+}
+
+type RevokeScopesRequest struct {
+	ClientID string `json:"clientID"`
+
+	SubjectID string `json:"subjectID"`
+
+	Scopes []string `json:"scopes"`
+}
+
+type RevokeScopesResponse struct {
+	Consent Consent `json:"consent"`
+}
+
+type RevokeSessionRequest struct {
+	SessionID string `json:"sessionID"`
+
+	RevokeTokens bool `json:"revokeTokens"`
+}
+
+type RevokeSessionResponse struct {
+}
+
+type Scope struct {
+	Name string `json:"name"`
+
+	DisplayName string `json:"displayName"`
+
+	Descriptions []ScopeDescription `json:"descriptions"`
+
+	RequiresConsent bool `json:"requiresConsent"`
+
+	Sensitive bool `json:"sensitive"`
+
+	Implies []string `json:"implies"`
+}
+
+type ScopeDescription struct {
+	Locale string `json:"locale"`
+
+	Description string `json:"description"`
+}
+
+type ScopeParameter struct {
+	Name string `json:"name"`
+
+	Value string `json:"value"`
+}
+
+type Session struct {
+	SessionID string `json:"sessionID"`
+
+	SubjectID string `json:"subjectID"`
+
+	ClientIDs []string `json:"clientIDs"`
+
+	UserAgent string `json:"userAgent"`
+
+	IPAddress string `json:"ipAddress"`
+
+	AuthenticatedAt int64 `json:"authenticatedAt"`
+
+	ExpiresAt int64 `json:"expiresAt"`
+
+	CreatedAt int64 `json:"createdAt"`
+}
+
+type ShowBackchannelAuthenticationRequest struct {
+	AuthReqID string `json:"authReqID"`
+}
+
+type ShowBackchannelAuthenticationResponse struct {
+	ClientID string `json:"clientID"`
+
+	RequestedScopes []string `json:"requestedScopes"`
+
+	LoginHint string `json:"loginHint"`
+
+	BindingMessage string `json:"bindingMessage"`
+
+	ExpiresAt int64 `json:"expiresAt"`
+}
+
 type ShowConsentChallengeRequest struct {
 	ConsentChallenge string `json:"consentChallenge"`
 }
@@ -264,4 +1784,56 @@ type ShowConsentChallengeResponse struct {
 	RequestedScopes []string `json:"requestedScopes"`
 
 	MissingScopes []string `json:"missingScopes"`
+
+	RequestedAuthorizationDetails []string `json:"requestedAuthorizationDetails"`
+
+	MissingAuthorizationDetails []string `json:"missingAuthorizationDetails"`
+
+	RequestedScopeMetadata []RequestedScope `json:"requestedScopeMetadata"`
+}
+
+type ShowLoginChallengeRequest struct {
+	LoginChallenge string `json:"loginChallenge"`
+}
+
+type ShowLoginChallengeResponse struct {
+	ClientID string `json:"clientID"`
+
+	ClientDomain string `json:"clientDomain"`
+
+	RequestedScopes []string `json:"requestedScopes"`
+
+	RequestedAuthorizationDetails []string `json:"requestedAuthorizationDetails"`
+
+	LoginHint string `json:"loginHint"`
+
+	UILocales []string `json:"uiLocales"`
+
+	Prompt string `json:"prompt"`
+
+	SessionExists bool `json:"sessionExists"`
+}
+
+type Violation struct {
+	ViolationID string `json:"violationID"`
+
+	Type string `json:"type"`
+
+	Description string `json:"description"`
+
+	ChallengeID string `json:"challengeID"`
+
+	ClientID string `json:"clientID"`
+
+	SubjectID string `json:"subjectID"`
+
+	RequestID string `json:"requestID"`
+
+	IPAddress string `json:"ipAddress"`
+
+	UserAgent string `json:"userAgent"`
+
+	Referer string `json:"referer"`
+
+	CreatedAt int64 `json:"createdAt"`
 }
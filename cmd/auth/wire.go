@@ -5,11 +5,25 @@ package main
 import (
 	"github.com/damejeras/auth/internal/admin"
 	"github.com/damejeras/auth/internal/app"
+	"github.com/damejeras/auth/internal/assertion"
+	"github.com/damejeras/auth/internal/ciba"
 	"github.com/damejeras/auth/internal/client"
 	"github.com/damejeras/auth/internal/consent"
+	"github.com/damejeras/auth/internal/device"
+	"github.com/damejeras/auth/internal/dpop"
+	"github.com/damejeras/auth/internal/exchange"
 	"github.com/damejeras/auth/internal/identity"
+	"github.com/damejeras/auth/internal/integrity"
+	"github.com/damejeras/auth/internal/jar"
+	"github.com/damejeras/auth/internal/logout"
 	"github.com/damejeras/auth/internal/oauth2"
+	"github.com/damejeras/auth/internal/par"
 	"github.com/damejeras/auth/internal/persistence"
+	"github.com/damejeras/auth/internal/rar"
+	"github.com/damejeras/auth/internal/resource"
+	"github.com/damejeras/auth/internal/scope"
+	"github.com/damejeras/auth/internal/session"
+	"github.com/damejeras/auth/internal/signing"
 	"github.com/google/wire"
 	"github.com/kkyr/fig"
 	"github.com/rs/zerolog"
@@ -35,13 +49,48 @@ func initOauth2HTTP(cfg *app.Config, logger *zerolog.Logger) (*http.Server, erro
 	wire.Build(
 		oauth2.NewHTTPServer,
 		oauth2.NewServer,
+		oauth2.NewTokenHandler,
+		oauth2.NewIntrospectionHandler,
 		oauth2.NewManager,
+		oauth2.NewTokenStore,
 		client.NewClientStorage,
 		identity.NewManager,
+		integrity.NewTracker,
+		integrity.NewGuard,
+		integrity.NewBinder,
 		persistence.NewDynamoDBClient,
 		persistence.NewIdentityChallengeRepository,
 		persistence.NewConsentChallengeRepository,
 		persistence.NewConsentRepository,
+		persistence.NewConsentHistoryRepository,
+		persistence.NewSessionRepository,
+		persistence.NewDeviceAuthorizationRepository,
+		persistence.NewDeviceGuessRepository,
+		persistence.NewPushedRequestRepository,
+		persistence.NewDPoPProofRepository,
+		persistence.NewBackchannelRequestRepository,
+		persistence.NewScopeRepository,
+		persistence.NewViolationRepository,
+		persistence.NewLockoutRepository,
+		session.NewCookie,
+		device.NewHandler,
+		ciba.NewHandler,
+		ciba.NewNotifier,
+		dpop.NewVerifier,
+		exchange.NewGrant,
+		par.NewHandler,
+		jar.NewHandler,
+		resource.NewHandler,
+		rar.NewHandler,
+		scope.NewHandler,
+		scope.NewRegistry,
+		resource.NewStorage,
+		assertion.NewGrant,
+		assertion.NewIssuerStorage,
+		logout.NewHandler,
+		logout.NewNotifier,
+		signing.NewSigner,
+		wire.Bind(new(session.Notifier), new(*logout.Notifier)),
 	)
 
 	return nil, nil
@@ -52,10 +101,27 @@ func initAdminHTTP(cfg *app.Config, logger *zerolog.Logger) (*http.Server, error
 		admin.NewHTTPServer,
 		identity.NewService,
 		consent.NewService,
+		session.NewService,
+		ciba.NewService,
+		ciba.NewNotifier,
+		scope.NewService,
+		integrity.NewService,
+		scope.NewRegistry,
+		client.NewClientStorage,
+		oauth2.NewTokenStore,
 		persistence.NewDynamoDBClient,
 		persistence.NewIdentityChallengeRepository,
 		persistence.NewConsentChallengeRepository,
 		persistence.NewConsentRepository,
+		persistence.NewConsentHistoryRepository,
+		persistence.NewSessionRepository,
+		persistence.NewBackchannelRequestRepository,
+		persistence.NewScopeRepository,
+		persistence.NewViolationRepository,
+		persistence.NewLockoutRepository,
+		logout.NewNotifier,
+		signing.NewSigner,
+		wire.Bind(new(session.Notifier), new(*logout.Notifier)),
 	)
 
 	return nil, nil
@@ -9,11 +9,25 @@ package main
 import (
 	"github.com/damejeras/auth/internal/admin"
 	"github.com/damejeras/auth/internal/app"
+	"github.com/damejeras/auth/internal/assertion"
+	"github.com/damejeras/auth/internal/ciba"
 	"github.com/damejeras/auth/internal/client"
 	"github.com/damejeras/auth/internal/consent"
+	"github.com/damejeras/auth/internal/device"
+	"github.com/damejeras/auth/internal/dpop"
+	"github.com/damejeras/auth/internal/exchange"
 	"github.com/damejeras/auth/internal/identity"
+	"github.com/damejeras/auth/internal/integrity"
+	"github.com/damejeras/auth/internal/jar"
+	"github.com/damejeras/auth/internal/logout"
 	"github.com/damejeras/auth/internal/oauth2"
+	"github.com/damejeras/auth/internal/par"
 	"github.com/damejeras/auth/internal/persistence"
+	"github.com/damejeras/auth/internal/rar"
+	"github.com/damejeras/auth/internal/resource"
+	"github.com/damejeras/auth/internal/scope"
+	"github.com/damejeras/auth/internal/session"
+	"github.com/damejeras/auth/internal/signing"
 	"github.com/kkyr/fig"
 	"github.com/rs/zerolog"
 	"net/http"
@@ -23,11 +37,12 @@ import (
 
 func initOauth2HTTP(cfg *app.Config, logger *zerolog.Logger) (*http.Server, error) {
 	dynamoDB := persistence.NewDynamoDBClient(cfg)
-	clientStore := client.NewClientStorage()
-	manager, err := oauth2.NewManager(dynamoDB, clientStore)
+	tokenStore, err := oauth2.NewTokenStore(dynamoDB)
 	if err != nil {
 		return nil, err
 	}
+	storage := client.NewClientStorage()
+	manager := oauth2.NewManager(tokenStore, storage)
 	challengeRepository, err := persistence.NewIdentityChallengeRepository(dynamoDB)
 	if err != nil {
 		return nil, err
@@ -40,9 +55,84 @@ func initOauth2HTTP(cfg *app.Config, logger *zerolog.Logger) (*http.Server, erro
 	if err != nil {
 		return nil, err
 	}
-	identityManager := identity.NewManager(challengeRepository, consentChallengeRepository, repository, logger, cfg)
+	historyRepository, err := persistence.NewConsentHistoryRepository(dynamoDB)
+	if err != nil {
+		return nil, err
+	}
+	scopeRepository, err := persistence.NewScopeRepository(dynamoDB)
+	if err != nil {
+		return nil, err
+	}
+	registry := scope.NewRegistry(scopeRepository, cfg)
+	sessionRepository, err := persistence.NewSessionRepository(dynamoDB)
+	if err != nil {
+		return nil, err
+	}
+	cookie := session.NewCookie(cfg)
+	signer, err := signing.NewSigner(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	notifier := logout.NewNotifier(storage, signer, cfg, logger)
+	violationRepository, err := persistence.NewViolationRepository(dynamoDB)
+	if err != nil {
+		return nil, err
+	}
+	lockoutRepository, err := persistence.NewLockoutRepository(dynamoDB)
+	if err != nil {
+		return nil, err
+	}
+	guard := integrity.NewGuard(violationRepository, lockoutRepository, cfg, logger)
+	tracker := integrity.NewTracker(violationRepository, guard, cfg, logger)
+	binder, err := integrity.NewBinder(cfg)
+	if err != nil {
+		return nil, err
+	}
+	identityManager := identity.NewManager(challengeRepository, consentChallengeRepository, repository, historyRepository, registry, storage, sessionRepository, cookie, notifier, tracker, guard, binder, logger, cfg)
 	server := oauth2.NewServer(manager, identityManager)
-	httpServer := oauth2.NewHTTPServer(server, logger)
+	deviceRepository, err := persistence.NewDeviceAuthorizationRepository(dynamoDB)
+	if err != nil {
+		return nil, err
+	}
+	guessRepository, err := persistence.NewDeviceGuessRepository(dynamoDB)
+	if err != nil {
+		return nil, err
+	}
+	handler := device.NewHandler(deviceRepository, guessRepository, storage, registry, sessionRepository, cookie, identityManager, cfg, logger)
+	cibaRepository, err := persistence.NewBackchannelRequestRepository(dynamoDB)
+	if err != nil {
+		return nil, err
+	}
+	cibaNotifier := ciba.NewNotifier(storage, cfg, logger)
+	cibaHandler := ciba.NewHandler(cibaRepository, storage, registry, cibaNotifier, cfg, logger)
+	grant := exchange.NewGrant(tokenStore, storage)
+	issuerStorage, err := assertion.NewIssuerStorage(cfg)
+	if err != nil {
+		return nil, err
+	}
+	assertionGrant := assertion.NewGrant(issuerStorage, repository, registry, cfg, logger)
+	dpopRepository, err := persistence.NewDPoPProofRepository(dynamoDB)
+	if err != nil {
+		return nil, err
+	}
+	verifier := dpop.NewVerifier(dpopRepository, cfg, logger)
+	resourceStorage, err := resource.NewStorage(cfg)
+	if err != nil {
+		return nil, err
+	}
+	rarHandler := rar.NewHandler(repository)
+	tokenHandler := oauth2.NewTokenHandler(server, tokenStore, storage, verifier, resourceStorage, rarHandler, handler, cibaHandler, grant, assertionGrant)
+	introspectionHandler := oauth2.NewIntrospectionHandler(server, tokenStore, storage)
+	pushedRequestRepository, err := persistence.NewPushedRequestRepository(dynamoDB)
+	if err != nil {
+		return nil, err
+	}
+	parHandler := par.NewHandler(pushedRequestRepository, storage, cfg, logger)
+	jarHandler := jar.NewHandler(storage, signer, logger)
+	resourceHandler := resource.NewHandler(resourceStorage, storage)
+	scopeHandler := scope.NewHandler(registry)
+	logoutHandler := logout.NewHandler(sessionRepository, cookie, storage, signer, notifier, logger)
+	httpServer := oauth2.NewHTTPServer(server, tokenHandler, introspectionHandler, handler, cibaHandler, parHandler, jarHandler, resourceHandler, scopeHandler, rarHandler, guard, logoutHandler, signer, logger)
 	return httpServer, nil
 }
 
@@ -52,17 +142,58 @@ func initAdminHTTP(cfg *app.Config, logger *zerolog.Logger) (*http.Server, error
 	if err != nil {
 		return nil, err
 	}
-	identityService := identity.NewService(challengeRepository)
+	storage := client.NewClientStorage()
+	identityService := identity.NewService(challengeRepository, storage)
 	repository, err := persistence.NewConsentRepository(dynamoDB)
 	if err != nil {
 		return nil, err
 	}
+	historyRepository, err := persistence.NewConsentHistoryRepository(dynamoDB)
+	if err != nil {
+		return nil, err
+	}
 	consentChallengeRepository, err := persistence.NewConsentChallengeRepository(dynamoDB)
 	if err != nil {
 		return nil, err
 	}
-	consentService := consent.NewService(repository, consentChallengeRepository)
-	server := admin.NewHTTPServer(identityService, consentService)
+	tokenStore, err := oauth2.NewTokenStore(dynamoDB)
+	if err != nil {
+		return nil, err
+	}
+	scopeRepository, err := persistence.NewScopeRepository(dynamoDB)
+	if err != nil {
+		return nil, err
+	}
+	registry := scope.NewRegistry(scopeRepository, cfg)
+	consentService := consent.NewService(repository, historyRepository, consentChallengeRepository, tokenStore, registry, cfg)
+	sessionRepository, err := persistence.NewSessionRepository(dynamoDB)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := signing.NewSigner(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	notifier := logout.NewNotifier(storage, signer, cfg, logger)
+	sessionService := session.NewService(sessionRepository, tokenStore, notifier)
+	cibaRepository, err := persistence.NewBackchannelRequestRepository(dynamoDB)
+	if err != nil {
+		return nil, err
+	}
+	violationRepository, err := persistence.NewViolationRepository(dynamoDB)
+	if err != nil {
+		return nil, err
+	}
+	lockoutRepository, err := persistence.NewLockoutRepository(dynamoDB)
+	if err != nil {
+		return nil, err
+	}
+	guard := integrity.NewGuard(violationRepository, lockoutRepository, cfg, logger)
+	cibaNotifier := ciba.NewNotifier(storage, cfg, logger)
+	backchannelService := ciba.NewService(cibaRepository, repository, registry, guard, cibaNotifier)
+	scopeService := scope.NewService(scopeRepository)
+	securityService := integrity.NewService(violationRepository, lockoutRepository)
+	server := admin.NewHTTPServer(identityService, consentService, sessionService, backchannelService, scopeService, securityService)
 	return server, nil
 }
 
@@ -1,19 +1,29 @@
 package admin
 
 import (
+	"expvar"
 	"github.com/damejeras/auth/api"
 	"github.com/pacedotdev/oto/otohttp"
 	"net/http"
 )
 
-func NewHTTPServer(identityService api.IdentityService, consentService api.ConsentService) *http.Server {
+func NewHTTPServer(identityService api.IdentityService, consentService api.ConsentService, sessionService api.SessionService, backchannelService api.BackchannelService, scopeService api.ScopeService, securityService api.SecurityService) *http.Server {
 	rpcServer := otohttp.NewServer()
 	rpcServer.Basepath = "/api/"
 
 	api.RegisterIdentityService(rpcServer, identityService)
 	api.RegisterConsentService(rpcServer, consentService)
+	api.RegisterSessionService(rpcServer, sessionService)
+	api.RegisterBackchannelService(rpcServer, backchannelService)
+	api.RegisterScopeService(rpcServer, scopeService)
+	api.RegisterSecurityService(rpcServer, securityService)
+
+	// runtime metrics, e.g. integrity violation counts, are exposed next to the API, as admin server is not public
+	mux := http.NewServeMux()
+	mux.Handle(rpcServer.Basepath, rpcServer)
+	mux.Handle("/debug/vars", expvar.Handler())
 
 	return &http.Server{
-		Handler: rpcServer,
+		Handler: mux,
 	}
 }
@@ -1,11 +1,14 @@
 package app
 
+import "time"
+
 type Config struct {
 	AdminConfig struct {
 		Port string `default:":9097"`
-	} `fig:"admin""`
+	} `fig:"admin"`
 	Oauth2Config struct {
-		Port string `default:":9096"`
+		Port   string `default:":9096"`
+		Issuer string `default:"http://localhost:9096"`
 	} `fig:"app"`
 	AWSConfig struct {
 		Region     string `validate:"required"`
@@ -16,8 +19,88 @@ type Config struct {
 	} `fig:"aws"`
 	ConsentProviderConfig struct {
 		Address string `default:"http://localhost:8888/consent"`
+		// RememberFor is default lifetime of consents subject asked to remember.
+		RememberFor time.Duration `default:"720h"`
 	} `fig:"consent_provider"`
 	IdentityProviderConfig struct {
 		Address string `default:"http://localhost:8888/auth"`
+		// BackchannelAddress is notified about backchannel authentication requests it should complete out-of-band.
+		BackchannelAddress string `default:"http://localhost:8888/backchannel"`
 	} `fig:"identity_provider"`
+	SigningConfig struct {
+		KeyFile string
+	} `fig:"signing"`
+	SessionConfig struct {
+		Secret   string        `validate:"required"`
+		Lifetime time.Duration `default:"24h"`
+	} `fig:"session"`
+	LogoutConfig struct {
+		Retries       int           `default:"3"`
+		RetryInterval time.Duration `default:"1s"`
+		Timeout       time.Duration `default:"5s"`
+	} `fig:"logout"`
+	DeviceConfig struct {
+		Lifetime time.Duration `default:"10m"`
+		Interval time.Duration `default:"5s"`
+		// Guesses is number of unknown user codes, which can be entered from IP address within GuessWindow.
+		Guesses     int           `default:"10"`
+		GuessWindow time.Duration `default:"10m"`
+	} `fig:"device"`
+	AssertionConfig struct {
+		// Issuers are trusted to issue JWT bearer assertions, their keys are read from JWKS files.
+		Issuers []struct {
+			Issuer     string
+			KeySetFile string
+			// Clients are allowed to present assertions of the issuer.
+			Clients []string
+			// SubjectPrefix namespaces assertion subjects, so issuer can not assert internal subjects.
+			// It defaults to issuer followed by "|".
+			SubjectPrefix string
+		}
+	} `fig:"assertion"`
+	PushedAuthorizationConfig struct {
+		Lifetime time.Duration `default:"90s"`
+	} `fig:"par"`
+	DPoPConfig struct {
+		ProofLifetime time.Duration `default:"1m"`
+	} `fig:"dpop"`
+	ScopeConfig struct {
+		// CatalogLifetime is how long registered scopes are cached. Admin API changes are visible in the same process
+		// immediately, other instances see them once their cache expires.
+		CatalogLifetime time.Duration `default:"1m"`
+	} `fig:"scope"`
+	ResourceConfig struct {
+		// Resources are protected resources tokens can be restricted to, each of them accepts its own scopes only.
+		Resources []struct {
+			URI    string
+			Scopes []string
+		}
+	} `fig:"resource"`
+	CIBAConfig struct {
+		Lifetime      time.Duration `default:"5m"`
+		Interval      time.Duration `default:"5s"`
+		Retries       int           `default:"3"`
+		RetryInterval time.Duration `default:"1s"`
+		Timeout       time.Duration `default:"5s"`
+	} `fig:"ciba"`
+	LockoutConfig struct {
+		// Window is period violations are counted in. Negative threshold disables lockout of its kind,
+		// zero is replaced with default value. Client and IP address lockouts are disabled by default,
+		// as violations of unauthenticated requests would lock out everyone using the client or behind the address.
+		Window             time.Duration `default:"15m"`
+		Duration           time.Duration `default:"15m"`
+		SubjectThreshold   int           `default:"5"`
+		ClientThreshold    int           `default:"-1"`
+		IPAddressThreshold int           `default:"-1"`
+	} `fig:"lockout"`
+	IntegrityConfig struct {
+		// FootprintMode is "stored" or "signed". Signed footprints are encrypted with FootprintSecret and carried
+		// in redirect URLs, so they do not depend on Referer header. Challenges keep request ID in both modes,
+		// sealed footprint is valid only for challenge of the same request.
+		FootprintMode     string `default:"stored"`
+		FootprintSecret   string
+		FootprintLifetime time.Duration `default:"30m"`
+		// ViolationRetention is how long violations are kept, they are deleted by DynamoDB afterwards.
+		ViolationRetention time.Duration `default:"720h"`
+	} `fig:"integrity"`
 }
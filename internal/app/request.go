@@ -3,6 +3,7 @@ package app
 import (
 	"context"
 	"github.com/segmentio/ksuid"
+	"net"
 	"net/http"
 )
 
@@ -41,3 +42,13 @@ func GetPreviousRequestID(r *http.Request) string {
 
 	return cookie.Value
 }
+
+// RemoteIP returns IP address of the client request was received from.
+func RemoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return host
+}
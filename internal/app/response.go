@@ -0,0 +1,29 @@
+package app
+
+import (
+	"encoding/json"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"net/http"
+)
+
+// WriteJSON writes JSON response with the same headers oauth2 server uses for token responses.
+func WriteJSON(w http.ResponseWriter, statusCode int, data map[string]interface{}) error {
+	w.Header().Set("Content-Type", "application/json;charset=UTF-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+	w.WriteHeader(statusCode)
+
+	return json.NewEncoder(w).Encode(data)
+}
+
+// WriteError writes oauth2 error response. Errors unknown to oauth2 server are written as server error.
+func WriteError(w http.ResponseWriter, err error) error {
+	if _, ok := errors.Descriptions[err]; !ok {
+		err = errors.ErrServerError
+	}
+
+	return WriteJSON(w, errors.StatusCodes[err], map[string]interface{}{
+		"error":             err.Error(),
+		"error_description": errors.Descriptions[err],
+	})
+}
@@ -0,0 +1,158 @@
+package assertion
+
+import (
+	"github.com/damejeras/auth/internal/app"
+	"github.com/damejeras/auth/internal/consent"
+	"github.com/damejeras/auth/internal/scope"
+	"github.com/damejeras/auth/pkg/dynamo"
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/manage"
+	"github.com/golang-jwt/jwt"
+	pkgErrors "github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"strings"
+	"time"
+)
+
+// GrantType is JWT bearer authorization grant type defined by RFC 7523.
+const GrantType oauth2.GrantType = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+
+// client presents new assertion when token expires, so refresh tokens are not issued.
+var tokenConfig = &manage.Config{AccessTokenExp: time.Hour}
+
+var validMethods = []string{
+	jwt.SigningMethodRS256.Alg(),
+	jwt.SigningMethodRS384.Alg(),
+	jwt.SigningMethodRS512.Alg(),
+	jwt.SigningMethodES256.Alg(),
+	jwt.SigningMethodES384.Alg(),
+	jwt.SigningMethodES512.Alg(),
+}
+
+// Grant issues tokens for assertions signed by issuers trusted by the client. Assertion subject is mapped
+// to subject ID in issuer's namespace, and token is issued only if subject has already consented to requested scopes.
+type Grant struct {
+	issuerStorage     *IssuerStorage
+	consentRepository consent.Repository
+	scopeRegistry     *scope.Registry
+	audiences         []string
+	logger            *zerolog.Logger
+}
+
+func NewGrant(
+	issuerStorage *IssuerStorage,
+	consentRepository consent.Repository,
+	scopeRegistry *scope.Registry,
+	cfg *app.Config,
+	logger *zerolog.Logger,
+) *Grant {
+	issuer := strings.TrimSuffix(cfg.Oauth2Config.Issuer, "/")
+
+	return &Grant{
+		issuerStorage:     issuerStorage,
+		consentRepository: consentRepository,
+		scopeRegistry:     scopeRegistry,
+		audiences:         []string{issuer, issuer + "/token"},
+		logger:            logger,
+	}
+}
+
+func (g *Grant) TokenConfig() *manage.Config {
+	return tokenConfig
+}
+
+func (g *Grant) ResolveTokenRequest(tgr *oauth2.TokenGenerateRequest, _ *dynamo.Token) error {
+	r := tgr.Request
+
+	assertion := r.FormValue("assertion")
+	if assertion == "" {
+		return errors.ErrInvalidRequest
+	}
+
+	issuer, claims, err := g.verify(tgr, assertion)
+	if err != nil {
+		g.logger.Debug().Err(err).Msgf("client %q presented invalid assertion", tgr.ClientID)
+
+		return errors.ErrInvalidGrant
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return errors.ErrInvalidGrant
+	}
+
+	subjectID := issuer.SubjectID(subject)
+
+	cs, err := g.consentRepository.FindByClientAndSubject(r.Context(), tgr.ClientID, subjectID)
+	if err != nil {
+		return pkgErrors.Wrapf(err, "find client's %q consent for subject %q", tgr.ClientID, subjectID)
+	}
+
+	if cs == nil || cs.Expired() {
+		return errors.ErrInvalidGrant
+	}
+
+	requestedScopes := cs.Scopes
+	if scope := r.FormValue("scope"); scope != "" {
+		requestedScopes = consent.BuildScopes(strings.Fields(scope))
+	}
+
+	unsatisfiedScopes, err := g.scopeRegistry.Unsatisfied(r.Context(), cs.Scopes.ToSlice(), requestedScopes.ToSlice())
+	if err != nil {
+		return pkgErrors.Wrap(err, "find unsatisfied scopes")
+	}
+
+	if len(unsatisfiedScopes) > 0 {
+		return errors.ErrInvalidScope
+	}
+
+	tgr.UserID = subjectID
+	tgr.Scope = strings.Join(requestedScopes.ToSlice(), " ")
+
+	return nil
+}
+
+// verify checks assertion signature with issuer's key and validates claims required by RFC 7523 section 3.
+func (g *Grant) verify(tgr *oauth2.TokenGenerateRequest, assertion string) (*Issuer, jwt.MapClaims, error) {
+	var issuer *Issuer
+
+	claims := make(jwt.MapClaims)
+	parser := jwt.Parser{ValidMethods: validMethods}
+
+	if _, err := parser.ParseWithClaims(assertion, claims, func(token *jwt.Token) (interface{}, error) {
+		issuerID, _ := claims["iss"].(string)
+
+		var err error
+		if issuer, err = g.issuerStorage.FindByID(tgr.Request.Context(), issuerID); err != nil {
+			return nil, pkgErrors.Wrapf(err, "find issuer %q", issuerID)
+		}
+
+		if issuer == nil || !issuer.Trusts(tgr.ClientID) {
+			return nil, pkgErrors.Errorf("issuer %q is not trusted by client %q", issuerID, tgr.ClientID)
+		}
+
+		kid, _ := token.Header["kid"].(string)
+
+		key := issuer.KeySet.Key(kid)
+		if key == nil {
+			return nil, pkgErrors.Errorf("issuer's %q key %q not found", issuerID, kid)
+		}
+
+		return key.PublicKey()
+	}); err != nil {
+		return nil, nil, pkgErrors.Wrap(err, "parse assertion")
+	}
+
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, nil, pkgErrors.New("assertion does not expire")
+	}
+
+	for i := range g.audiences {
+		if claims.VerifyAudience(g.audiences[i], true) {
+			return issuer, claims, nil
+		}
+	}
+
+	return nil, nil, pkgErrors.New("assertion is not intended for this server")
+}
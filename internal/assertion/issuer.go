@@ -0,0 +1,78 @@
+package assertion
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/damejeras/auth/internal/app"
+	"github.com/damejeras/auth/internal/signing"
+	"github.com/pkg/errors"
+	"io/ioutil"
+)
+
+// Issuer is trusted party, which signs assertions with one of the keys in its key set.
+type Issuer struct {
+	ID            string
+	KeySet        signing.JSONWebKeySet
+	ClientIDs     []string
+	SubjectPrefix string
+}
+
+// Trusts reports whether client is allowed to present assertions of the issuer.
+func (i *Issuer) Trusts(clientID string) bool {
+	for j := range i.ClientIDs {
+		if i.ClientIDs[j] == clientID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SubjectID maps assertion subject to subject ID in issuer's namespace.
+func (i *Issuer) SubjectID(subject string) string {
+	return i.SubjectPrefix + subject
+}
+
+type IssuerStorage struct {
+	issuers map[string]*Issuer
+}
+
+func NewIssuerStorage(cfg *app.Config) (*IssuerStorage, error) {
+	storage := IssuerStorage{
+		issuers: make(map[string]*Issuer),
+	}
+
+	for _, issuer := range cfg.AssertionConfig.Issuers {
+		if issuer.Issuer == "" || issuer.KeySetFile == "" || len(issuer.Clients) == 0 {
+			return nil, errors.New("assertion issuer, key set file and clients are required")
+		}
+
+		keySetBytes, err := ioutil.ReadFile(issuer.KeySetFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "read issuer's %q key set", issuer.Issuer)
+		}
+
+		var keySet signing.JSONWebKeySet
+		if err := json.Unmarshal(keySetBytes, &keySet); err != nil {
+			return nil, errors.Wrapf(err, "unmarshal issuer's %q key set", issuer.Issuer)
+		}
+
+		subjectPrefix := issuer.SubjectPrefix
+		if subjectPrefix == "" {
+			subjectPrefix = issuer.Issuer + "|"
+		}
+
+		storage.issuers[issuer.Issuer] = &Issuer{
+			ID:            issuer.Issuer,
+			KeySet:        keySet,
+			ClientIDs:     issuer.Clients,
+			SubjectPrefix: subjectPrefix,
+		}
+	}
+
+	return &storage, nil
+}
+
+func (s *IssuerStorage) FindByID(_ context.Context, id string) (*Issuer, error) {
+	return s.issuers[id], nil
+}
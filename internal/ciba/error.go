@@ -0,0 +1,17 @@
+package ciba
+
+import (
+	stdErrors "errors"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"net/http"
+)
+
+// ErrInvalidBindingMessage is backchannel authentication endpoint error defined by CIBA Core section 13.
+var ErrInvalidBindingMessage = stdErrors.New("invalid_binding_message")
+
+// register error with oauth2 server, so it is rendered as regular oauth2 error.
+func init() {
+	errors.Descriptions[ErrInvalidBindingMessage] = "The binding message is invalid or unacceptable for use in the context of the given request"
+
+	errors.StatusCodes[ErrInvalidBindingMessage] = http.StatusBadRequest
+}
@@ -0,0 +1,232 @@
+package ciba
+
+import (
+	"github.com/damejeras/auth/internal/app"
+	"github.com/damejeras/auth/internal/client"
+	"github.com/damejeras/auth/internal/device"
+	"github.com/damejeras/auth/internal/scope"
+	"github.com/damejeras/auth/pkg/dynamo"
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/manage"
+	"github.com/go-oauth2/oauth2/v4/server"
+	pkgErrors "github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/segmentio/ksuid"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// GrantType is backchannel authentication grant type defined by CIBA Core.
+const GrantType oauth2.GrantType = "urn:openid:params:grant-type:ciba"
+
+// Token delivery modes clients can be registered with. Push mode is not supported.
+const (
+	ModePoll = "poll"
+	ModePing = "ping"
+)
+
+const (
+	paramAuthReqID = "auth_req_id"
+
+	// binding message is displayed on both consumption and authentication devices, so it has to be short.
+	maxBindingMessageLength = 64
+
+	slowDownIncrement = 5 * time.Second
+)
+
+// Handler serves backchannel authentication endpoint and backchannel authentication grant.
+type Handler struct {
+	repository    Repository
+	clientStorage *client.Storage
+	registry      *scope.Registry
+	notifier      *Notifier
+	lifetime      time.Duration
+	interval      time.Duration
+	logger        *zerolog.Logger
+}
+
+func NewHandler(
+	repository Repository,
+	clientStorage *client.Storage,
+	registry *scope.Registry,
+	notifier *Notifier,
+	cfg *app.Config,
+	logger *zerolog.Logger,
+) *Handler {
+	return &Handler{
+		repository:    repository,
+		clientStorage: clientStorage,
+		registry:      registry,
+		notifier:      notifier,
+		lifetime:      cfg.CIBAConfig.Lifetime,
+		interval:      cfg.CIBAConfig.Interval,
+		logger:        logger,
+	}
+}
+
+// HandleBackchannelAuthenticationRequest starts authentication of the user identified by login hint
+// and passes it to identity provider, which completes it out-of-band.
+func (h *Handler) HandleBackchannelAuthenticationRequest(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodPost {
+		return app.WriteError(w, errors.ErrInvalidRequest)
+	}
+
+	clientID, clientSecret, err := server.ClientBasicHandler(r)
+	if err != nil {
+		return app.WriteError(w, err)
+	}
+
+	cl, err := h.clientStorage.FindByID(r.Context(), clientID)
+	if err != nil {
+		app.WriteError(w, errors.ErrServerError)
+
+		return pkgErrors.Wrapf(err, "find client %q", clientID)
+	}
+
+	if cl == nil || cl.Secret != clientSecret {
+		return app.WriteError(w, errors.ErrInvalidClient)
+	}
+
+	switch {
+	case cl.BackchannelTokenDeliveryMode == ModePoll:
+	case cl.BackchannelTokenDeliveryMode == ModePing && cl.BackchannelClientNotificationEndpoint != "":
+	default:
+		return app.WriteError(w, errors.ErrUnauthorizedClient)
+	}
+
+	// users are identified by login hint only, identity provider resolves it to the subject
+	loginHint := r.FormValue("login_hint")
+	if loginHint == "" || r.FormValue("login_hint_token") != "" || r.FormValue("id_token_hint") != "" {
+		return app.WriteError(w, errors.ErrInvalidRequest)
+	}
+
+	bindingMessage := r.FormValue("binding_message")
+	if utf8.RuneCountInString(bindingMessage) > maxBindingMessageLength {
+		return app.WriteError(w, ErrInvalidBindingMessage)
+	}
+
+	notificationToken := r.FormValue("client_notification_token")
+	if cl.BackchannelTokenDeliveryMode == ModePing && notificationToken == "" {
+		return app.WriteError(w, errors.ErrInvalidRequest)
+	}
+
+	// backchannel authentication requests do not pass authorization endpoint middlewares, which reject unknown scopes
+	_, err = h.registry.Resolve(r.Context(), strings.Fields(r.FormValue("scope")))
+	switch {
+	case err == errors.ErrInvalidScope:
+		return app.WriteError(w, err)
+	case err != nil:
+		app.WriteError(w, errors.ErrServerError)
+
+		return pkgErrors.Wrap(err, "resolve requested scopes")
+	}
+
+	lifetime := h.lifetime
+	if requestedExpiry := r.FormValue("requested_expiry"); requestedExpiry != "" {
+		seconds, err := strconv.Atoi(requestedExpiry)
+		if err != nil || seconds <= 0 {
+			return app.WriteError(w, errors.ErrInvalidRequest)
+		}
+
+		if expiry := time.Duration(seconds) * time.Second; expiry < lifetime {
+			lifetime = expiry
+		}
+	}
+
+	now := time.Now()
+
+	request := Request{
+		ID:                ksuid.New().String(),
+		ClientID:          cl.ID,
+		Scope:             r.FormValue("scope"),
+		LoginHint:         loginHint,
+		BindingMessage:    bindingMessage,
+		NotificationToken: notificationToken,
+		Interval:          h.interval,
+		ExpiresAt:         now.Add(lifetime),
+		CreatedAt:         now,
+	}
+
+	if err := h.repository.Store(r.Context(), &request); err != nil {
+		app.WriteError(w, errors.ErrServerError)
+
+		return pkgErrors.Wrap(err, "store backchannel authentication request")
+	}
+
+	h.notifier.NotifyIdentityProvider(&request)
+
+	return app.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		paramAuthReqID: request.ID,
+		"expires_in":   int64(lifetime / time.Second),
+		"interval":     int64(h.interval / time.Second),
+	})
+}
+
+// TokenConfig returns the same token configuration as authorization code grant has, as both are used by end users.
+func (h *Handler) TokenConfig() *manage.Config {
+	return manage.DefaultAuthorizeCodeTokenCfg
+}
+
+// ResolveTokenRequest resolves backchannel authentication grant. It answers pending requests with the same
+// polling errors device code grant does, completed request is consumed and its subject and scope are used for the token.
+func (h *Handler) ResolveTokenRequest(tgr *oauth2.TokenGenerateRequest, _ *dynamo.Token) error {
+	ctx := tgr.Request.Context()
+
+	authReqID := tgr.Request.FormValue(paramAuthReqID)
+	if authReqID == "" {
+		return errors.ErrInvalidRequest
+	}
+
+	request, err := h.repository.FindByID(ctx, authReqID)
+	if err != nil {
+		return pkgErrors.Wrap(err, "find backchannel authentication request")
+	}
+
+	if request == nil || request.ClientID != tgr.ClientID {
+		return errors.ErrInvalidGrant
+	}
+
+	switch {
+	case request.Expired():
+		if err := h.repository.Delete(ctx, request); err != nil {
+			return pkgErrors.Wrap(err, "delete backchannel authentication request")
+		}
+
+		return device.ErrExpiredToken
+	case request.Denied:
+		if err := h.repository.Delete(ctx, request); err != nil {
+			return pkgErrors.Wrap(err, "delete backchannel authentication request")
+		}
+
+		return errors.ErrAccessDenied
+	case request.Pending():
+		pollErr := device.ErrAuthorizationPending
+
+		now := time.Now()
+		if now.Sub(request.PolledAt) < request.Interval {
+			request.Interval += slowDownIncrement
+			pollErr = device.ErrSlowDown
+		}
+
+		request.PolledAt = now
+
+		if err := h.repository.UpdateWithPoll(ctx, request); err != nil {
+			return pkgErrors.Wrap(err, "update backchannel authentication request with poll")
+		}
+
+		return pollErr
+	}
+
+	if err := h.repository.Delete(ctx, request); err != nil {
+		return pkgErrors.Wrap(err, "delete backchannel authentication request")
+	}
+
+	tgr.UserID = request.SubjectID
+	tgr.Scope = request.Scope
+
+	return nil
+}
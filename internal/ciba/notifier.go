@@ -0,0 +1,110 @@
+package ciba
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/damejeras/auth/internal/app"
+	"github.com/damejeras/auth/internal/client"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"net/http"
+	"time"
+)
+
+// Notifier delivers backchannel authentication requests to identity provider and, in ping mode,
+// notifies clients that requests have been completed.
+type Notifier struct {
+	clientStorage       *client.Storage
+	httpClient          *http.Client
+	identityProviderURI string
+	retries             int
+	retryInterval       time.Duration
+	logger              *zerolog.Logger
+}
+
+func NewNotifier(clientStorage *client.Storage, cfg *app.Config, logger *zerolog.Logger) *Notifier {
+	return &Notifier{
+		clientStorage:       clientStorage,
+		httpClient:          &http.Client{Timeout: cfg.CIBAConfig.Timeout},
+		identityProviderURI: cfg.IdentityProviderConfig.BackchannelAddress,
+		retries:             cfg.CIBAConfig.Retries,
+		retryInterval:       cfg.CIBAConfig.RetryInterval,
+		logger:              logger,
+	}
+}
+
+// NotifyIdentityProvider tells identity provider about new request in background. Identity provider is expected
+// to look the request up through admin API and complete or reject it after authenticating the user.
+func (n *Notifier) NotifyIdentityProvider(request *Request) {
+	go n.deliver(n.identityProviderURI, "", request.ID)
+}
+
+// NotifyClient pings client registered for ping mode, so it can fetch the token without waiting for next poll.
+func (n *Notifier) NotifyClient(ctx context.Context, request *Request) error {
+	cl, err := n.clientStorage.FindByID(ctx, request.ClientID)
+	if err != nil {
+		return errors.Wrapf(err, "find client %q", request.ClientID)
+	}
+
+	if cl == nil || cl.BackchannelTokenDeliveryMode != ModePing {
+		return nil
+	}
+
+	go n.deliver(cl.BackchannelClientNotificationEndpoint, request.NotificationToken, request.ID)
+
+	return nil
+}
+
+func (n *Notifier) deliver(uri, bearer, authReqID string) {
+	interval := n.retryInterval
+
+	for attempt := 0; ; attempt++ {
+		err := n.post(uri, bearer, authReqID)
+		if err == nil {
+			return
+		}
+
+		if attempt >= n.retries {
+			n.logger.Error().Err(err).Msgf("deliver backchannel authentication request %q to %q", authReqID, uri)
+
+			return
+		}
+
+		n.logger.Warn().Err(err).Msgf("deliver backchannel authentication request %q to %q, retrying in %s", authReqID, uri, interval)
+
+		time.Sleep(interval)
+		interval *= 2
+	}
+}
+
+func (n *Notifier) post(uri, bearer, authReqID string) error {
+	body, err := json.Marshal(map[string]string{paramAuthReqID: authReqID})
+	if err != nil {
+		return errors.Wrap(err, "marshal request body")
+	}
+
+	request, err := http.NewRequest(http.MethodPost, uri, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "create request")
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+
+	if bearer != "" {
+		request.Header.Set("Authorization", "Bearer "+bearer)
+	}
+
+	response, err := n.httpClient.Do(request)
+	if err != nil {
+		return errors.Wrap(err, "execute request")
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusNoContent {
+		return errors.Errorf("unexpected status code %d", response.StatusCode)
+	}
+
+	return nil
+}
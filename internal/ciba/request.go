@@ -0,0 +1,49 @@
+package ciba
+
+import (
+	"context"
+	"time"
+)
+
+// Request is pending backchannel authentication request. Client polls token endpoint with its ID,
+// while identity provider authenticates the user out-of-band and completes or rejects the request.
+type Request struct {
+	ID             string
+	ClientID       string
+	Scope          string
+	LoginHint      string
+	BindingMessage string
+	// NotificationToken is bearer token client expects when it is pinged about completed request.
+	NotificationToken string
+	SubjectID         string
+	Denied            bool
+	Interval          time.Duration
+	PolledAt          time.Time
+	ExpiresAt         time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (r *Request) Expired() bool {
+	return time.Now().After(r.ExpiresAt)
+}
+
+// Approved reports whether identity provider has authenticated the user.
+func (r *Request) Approved() bool {
+	return r.SubjectID != ""
+}
+
+// Pending reports whether identity provider has neither completed nor rejected the request yet.
+func (r *Request) Pending() bool {
+	return !r.Approved() && !r.Denied
+}
+
+type Repository interface {
+	Store(context.Context, *Request) error
+	UpdateWithApproval(context.Context, *Request) error
+	UpdateWithDenial(context.Context, *Request) error
+	UpdateWithPoll(context.Context, *Request) error
+	FindByID(context.Context, string) (*Request, error)
+	Delete(context.Context, *Request) error
+}
@@ -0,0 +1,142 @@
+package ciba
+
+import (
+	"context"
+	"github.com/damejeras/auth/api"
+	"github.com/damejeras/auth/internal/consent"
+	"github.com/damejeras/auth/internal/integrity"
+	"github.com/damejeras/auth/internal/scope"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+type service struct {
+	repository        Repository
+	consentRepository consent.Repository
+	scopeRegistry     *scope.Registry
+	integrityGuard    *integrity.Guard
+	notifier          *Notifier
+}
+
+func NewService(
+	repository Repository,
+	consentRepository consent.Repository,
+	scopeRegistry *scope.Registry,
+	integrityGuard *integrity.Guard,
+	notifier *Notifier,
+) api.BackchannelService {
+	return &service{
+		repository:        repository,
+		consentRepository: consentRepository,
+		scopeRegistry:     scopeRegistry,
+		integrityGuard:    integrityGuard,
+		notifier:          notifier,
+	}
+}
+
+func (s *service) ShowBackchannelAuthentication(ctx context.Context, request api.ShowBackchannelAuthenticationRequest) (*api.ShowBackchannelAuthenticationResponse, error) {
+	authentication, err := s.findPending(ctx, request.AuthReqID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.ShowBackchannelAuthenticationResponse{
+		ClientID:        authentication.ClientID,
+		RequestedScopes: strings.Fields(authentication.Scope),
+		LoginHint:       authentication.LoginHint,
+		BindingMessage:  authentication.BindingMessage,
+		ExpiresAt:       authentication.ExpiresAt.Unix(),
+	}, nil
+}
+
+func (s *service) CompleteBackchannelAuthentication(ctx context.Context, request api.CompleteBackchannelAuthenticationRequest) (*api.CompleteBackchannelAuthenticationResponse, error) {
+	if request.SubjectID == "" {
+		return nil, errors.New("subject is required")
+	}
+
+	authentication, err := s.findPending(ctx, request.AuthReqID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.authorizeSubject(ctx, authentication, request.SubjectID); err != nil {
+		return nil, err
+	}
+
+	authentication.SubjectID = request.SubjectID
+
+	if err := s.repository.UpdateWithApproval(ctx, authentication); err != nil {
+		return nil, errors.Wrap(err, "update backchannel authentication request with approval")
+	}
+
+	if err := s.notifier.NotifyClient(ctx, authentication); err != nil {
+		return nil, errors.Wrap(err, "notify client")
+	}
+
+	return &api.CompleteBackchannelAuthenticationResponse{}, nil
+}
+
+func (s *service) RejectBackchannelAuthentication(ctx context.Context, request api.RejectBackchannelAuthenticationRequest) (*api.RejectBackchannelAuthenticationResponse, error) {
+	authentication, err := s.findPending(ctx, request.AuthReqID)
+	if err != nil {
+		return nil, err
+	}
+
+	authentication.Denied = true
+
+	if err := s.repository.UpdateWithDenial(ctx, authentication); err != nil {
+		return nil, errors.Wrap(err, "update backchannel authentication request with denial")
+	}
+
+	if err := s.notifier.NotifyClient(ctx, authentication); err != nil {
+		return nil, errors.Wrap(err, "notify client")
+	}
+
+	return &api.RejectBackchannelAuthenticationResponse{}, nil
+}
+
+// authorizeSubject checks that subject is not locked out and has already consented to requested scopes,
+// as backchannel authentication does not pass through consent provider.
+func (s *service) authorizeSubject(ctx context.Context, authentication *Request, subjectID string) error {
+	lockout, err := s.integrityGuard.Locked(ctx, integrity.LockoutSubject, subjectID)
+	if err != nil {
+		return errors.Wrapf(err, "check subject %q lockout", subjectID)
+	}
+
+	if lockout != nil {
+		return errors.Errorf("subject %q is locked out", subjectID)
+	}
+
+	cs, err := s.consentRepository.FindByClientAndSubject(ctx, authentication.ClientID, subjectID)
+	if err != nil {
+		return errors.Wrapf(err, "find client's %q consent for subject %q", authentication.ClientID, subjectID)
+	}
+
+	if cs == nil || cs.Expired() {
+		return errors.Errorf("subject %q has not consented to client %q", subjectID, authentication.ClientID)
+	}
+
+	unsatisfiedScopes, err := s.scopeRegistry.Unsatisfied(ctx, cs.Scopes.ToSlice(), strings.Fields(authentication.Scope))
+	if err != nil {
+		return errors.Wrap(err, "find unsatisfied scopes")
+	}
+
+	if len(unsatisfiedScopes) > 0 {
+		return errors.Errorf("subject %q has not consented to scopes %q", subjectID, strings.Join(unsatisfiedScopes, " "))
+	}
+
+	return nil
+}
+
+func (s *service) findPending(ctx context.Context, id string) (*Request, error) {
+	authentication, err := s.repository.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "find backchannel authentication request")
+	}
+
+	if authentication == nil || authentication.Expired() || !authentication.Pending() {
+		return nil, errors.New("invalid backchannel authentication request")
+	}
+
+	return authentication, nil
+}
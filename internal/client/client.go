@@ -0,0 +1,80 @@
+package client
+
+import "github.com/damejeras/auth/internal/signing"
+
+// Client is registered oauth2 client. It implements oauth2.ClientInfo, so it can be used by oauth2 manager.
+type Client struct {
+	ID                     string
+	Secret                 string
+	Domain                 string
+	UserID                 string
+	PostLogoutRedirectURIs []string
+	BackchannelLogoutURI   string
+	FrontchannelLogoutURI  string
+	ExchangeAudiences      []string
+	// RequirePushedAuthorizationRequests makes client pass authorization parameters through pushed authorization requests only.
+	RequirePushedAuthorizationRequests bool
+	// KeySet holds client's public keys, which are used to verify request objects.
+	KeySet signing.JSONWebKeySet
+	// RequestURIs are URLs server is allowed to fetch request objects from.
+	RequestURIs []string
+	// RequireSignedRequestObject makes client pass authorization parameters in signed request objects only.
+	RequireSignedRequestObject bool
+	// SkipConsent marks trusted first-party client, subjects consent to everything it requests implicitly.
+	SkipConsent bool
+	// ImplicitConsentScopes are scopes subjects consent to implicitly when authorizing the client.
+	ImplicitConsentScopes []string
+	// BackchannelTokenDeliveryMode is "poll" or "ping" for clients allowed to use backchannel authentication.
+	BackchannelTokenDeliveryMode string
+	// BackchannelClientNotificationEndpoint is pinged when backchannel authentication request is completed.
+	BackchannelClientNotificationEndpoint string
+}
+
+func (c *Client) GetID() string {
+	return c.ID
+}
+
+func (c *Client) GetSecret() string {
+	return c.Secret
+}
+
+func (c *Client) GetDomain() string {
+	return c.Domain
+}
+
+func (c *Client) GetUserID() string {
+	return c.UserID
+}
+
+// HasPostLogoutRedirectURI reports whether uri exactly matches one of registered post logout redirect URIs.
+func (c *Client) HasPostLogoutRedirectURI(uri string) bool {
+	for i := range c.PostLogoutRedirectURIs {
+		if c.PostLogoutRedirectURIs[i] == uri {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CanExchangeInto reports whether client is allowed to exchange tokens for tokens of given audience.
+func (c *Client) CanExchangeInto(audience string) bool {
+	for i := range c.ExchangeAudiences {
+		if c.ExchangeAudiences[i] == audience {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasRequestURI reports whether uri exactly matches one of registered request URIs.
+func (c *Client) HasRequestURI(uri string) bool {
+	for i := range c.RequestURIs {
+		if c.RequestURIs[i] == uri {
+			return true
+		}
+	}
+
+	return false
+}
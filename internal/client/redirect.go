@@ -0,0 +1,60 @@
+package client
+
+import (
+	"github.com/damejeras/auth/internal/app"
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/manage"
+	"net/http"
+	"net/url"
+)
+
+// WriteAuthorizeError redirects user agent back to client with authorization endpoint error, as RFC 6749
+// section 4.1.2.1 requires. Error is written to user agent only if client is unknown or redirect URI does not
+// belong to it, so user agent is never redirected to unverified URI. Client's domain is used, if request
+// does not have redirect URI, the same way oauth2 server does it.
+func (s *Storage) WriteAuthorizeError(w http.ResponseWriter, r *http.Request, err error) error {
+	query := r.URL.Query()
+
+	cl, findErr := s.FindByID(r.Context(), query.Get("client_id"))
+	if findErr != nil || cl == nil {
+		return app.WriteError(w, err)
+	}
+
+	redirectURI := query.Get("redirect_uri")
+	if redirectURI == "" {
+		redirectURI = cl.GetDomain()
+	} else if manage.DefaultValidateURI(cl.GetDomain(), redirectURI) != nil {
+		return app.WriteError(w, err)
+	}
+
+	location, parseErr := url.Parse(redirectURI)
+	if parseErr != nil {
+		return app.WriteError(w, err)
+	}
+
+	if _, ok := errors.Descriptions[err]; !ok {
+		err = errors.ErrServerError
+	}
+
+	values := location.Query()
+	values.Set("error", err.Error())
+	values.Set("error_description", errors.Descriptions[err])
+
+	if state := query.Get("state"); state != "" {
+		values.Set("state", state)
+	}
+
+	// implicit grant returns parameters in fragment, so they are not sent to client's server
+	if oauth2.ResponseType(query.Get("response_type")) == oauth2.Token {
+		location.RawQuery = ""
+		location.Fragment = values.Encode()
+	} else {
+		location.RawQuery = values.Encode()
+	}
+
+	w.Header().Set("Location", location.String())
+	w.WriteHeader(http.StatusFound)
+
+	return nil
+}
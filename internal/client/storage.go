@@ -1,18 +1,38 @@
 package client
 
 import (
-	"github.com/go-oauth2/oauth2/v4/models"
-	"github.com/go-oauth2/oauth2/v4/store"
+	"context"
+	"github.com/go-oauth2/oauth2/v4"
 )
 
-func NewClientStorage() *store.ClientStore {
+type Storage struct {
+	clients map[string]*Client
+}
+
+func NewClientStorage() *Storage {
 	// TODO: replace with proper implementation
-	clientStore := store.NewClientStore()
-	clientStore.Set("test", &models.Client{
-		ID:     "test",
-		Secret: "test",
-		Domain: "https://oauth.tools/",
-	})
+	return &Storage{
+		clients: map[string]*Client{
+			"test": {
+				ID:                     "test",
+				Secret:                 "test",
+				Domain:                 "https://oauth.tools/",
+				PostLogoutRedirectURIs: []string{"https://oauth.tools/"},
+			},
+		},
+	}
+}
+
+// GetByID implements oauth2.ClientStore.
+func (s *Storage) GetByID(ctx context.Context, id string) (oauth2.ClientInfo, error) {
+	client, err := s.FindByID(ctx, id)
+	if err != nil || client == nil {
+		return nil, err
+	}
+
+	return client, nil
+}
 
-	return clientStore
+func (s *Storage) FindByID(_ context.Context, id string) (*Client, error) {
+	return s.clients[id], nil
 }
@@ -0,0 +1,126 @@
+package consent
+
+import (
+	"encoding/json"
+	"github.com/pkg/errors"
+	"reflect"
+)
+
+// AuthorizationDetail is single object of authorization details defined by RFC 9396.
+// Its members depend on its type, e.g. payment initiation carries instructed amount and creditor.
+type AuthorizationDetail map[string]interface{}
+
+// Type returns authorization details type, which is the only member required for every object.
+func (d AuthorizationDetail) Type() string {
+	detailType, _ := d["type"].(string)
+
+	return detailType
+}
+
+// ParseAuthorizationDetail parses single authorization details object from JSON.
+func ParseAuthorizationDetail(value string) (AuthorizationDetail, error) {
+	var detail AuthorizationDetail
+	if err := json.Unmarshal([]byte(value), &detail); err != nil {
+		return nil, errors.Wrap(err, "unmarshal authorization detail")
+	}
+
+	if detail.Type() == "" {
+		return nil, errors.New("authorization detail type is required")
+	}
+
+	return detail, nil
+}
+
+type AuthorizationDetails []AuthorizationDetail
+
+// ParseAuthorizationDetails parses authorization_details request parameter, which is JSON array of objects.
+// Empty parameter results in nil details.
+func ParseAuthorizationDetails(value string) (AuthorizationDetails, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var details AuthorizationDetails
+	if err := json.Unmarshal([]byte(value), &details); err != nil {
+		return nil, errors.Wrap(err, "unmarshal authorization details")
+	}
+
+	for i := range details {
+		if details[i].Type() == "" {
+			return nil, errors.New("authorization detail type is required")
+		}
+	}
+
+	return details, nil
+}
+
+// Contains reports whether one of the details is equal to given detail. Details are compared by value,
+// so the same detail requested again matches the one consented earlier.
+func (d AuthorizationDetails) Contains(detail AuthorizationDetail) bool {
+	for i := range d {
+		if reflect.DeepEqual(d[i], detail) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (d AuthorizationDetails) HasAll(details AuthorizationDetails) bool {
+	for i := range details {
+		if !d.Contains(details[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Intersect returns details, which are contained in both sets.
+func (d AuthorizationDetails) Intersect(details AuthorizationDetails) AuthorizationDetails {
+	result := make(AuthorizationDetails, 0)
+	for i := range d {
+		if details.Contains(d[i]) {
+			result = append(result, d[i])
+		}
+	}
+
+	return result
+}
+
+func (d AuthorizationDetails) Diff(details AuthorizationDetails) AuthorizationDetails {
+	result := make(AuthorizationDetails, 0)
+	for i := range d {
+		if !details.Contains(d[i]) {
+			result = append(result, d[i])
+		}
+	}
+
+	return result
+}
+
+func (d AuthorizationDetails) Merge(details AuthorizationDetails) AuthorizationDetails {
+	result := append(make(AuthorizationDetails, 0, len(d)+len(details)), d...)
+	for i := range details {
+		if !result.Contains(details[i]) {
+			result = append(result, details[i])
+		}
+	}
+
+	return result
+}
+
+// ToSlice returns details as JSON objects, which is how they are exposed to login and consent providers.
+func (d AuthorizationDetails) ToSlice() []string {
+	result := make([]string, 0, len(d))
+	for i := range d {
+		detailBytes, err := json.Marshal(d[i])
+		if err != nil {
+			continue
+		}
+
+		result = append(result, string(detailBytes))
+	}
+
+	return result
+}
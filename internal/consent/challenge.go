@@ -7,20 +7,30 @@ import (
 )
 
 type Challenge struct {
-	ID              string
-	Verifier        string
-	ClientID        string
-	SubjectID       string
-	RequestedScopes Scopes
-	MissingScopes   Scopes
-	GrantedScopes   Scopes
-	Footprint       *integrity.Footprint
-	Used            bool
+	ID                            string
+	Verifier                      string
+	ClientID                      string
+	SubjectID                     string
+	RequestedScopes               Scopes
+	MissingScopes                 Scopes
+	GrantedScopes                 Scopes
+	RequestedAuthorizationDetails AuthorizationDetails
+	MissingAuthorizationDetails   AuthorizationDetails
+	GrantedAuthorizationDetails   AuthorizationDetails
+	Rejection                     *Rejection
+	Footprint                     *integrity.Footprint
+	Used                          bool
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
+// Rejection is the error consent provider responded with instead of granting scopes.
+type Rejection struct {
+	Error       string
+	Description string
+}
+
 type Scopes map[string]struct{}
 
 func BuildScopes(scopes []string) Scopes {
@@ -84,7 +94,8 @@ func (s Scopes) Merge(scopes Scopes) Scopes {
 
 type ChallengeRepository interface {
 	Store(context.Context, *Challenge) error
-	UpdateWithGrantedScopes(context.Context, *Challenge) error
+	UpdateWithGrant(context.Context, *Challenge) error
+	UpdateWithRejection(context.Context, *Challenge) error
 	FindByID(context.Context, string) (*Challenge, error)
 	FindByVerifier(context.Context, string) (*Challenge, error)
 	Delete(context.Context, *Challenge) error
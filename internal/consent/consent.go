@@ -6,17 +6,31 @@ import (
 )
 
 type Consent struct {
-	ID        string
-	ClientID  string
-	SubjectID string
-	Scopes    Scopes
+	ID                   string
+	ClientID             string
+	SubjectID            string
+	Scopes               Scopes
+	AuthorizationDetails AuthorizationDetails
+	// ExpiresAt is zero for consents given before expiry was introduced, they never expire.
+	ExpiresAt time.Time
+	// OnceAuthorizationDetails are details granted for single authorization, which subject has not remembered.
+	// They are kept apart from remembered details, so token endpoint can pass them to the token without remembering them.
+	OnceAuthorizationDetails AuthorizationDetails
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
+// Expired reports whether consent can no longer be used to skip consent challenge.
+// Consents not remembered by subject expire as soon as they are given.
+func (c *Consent) Expired() bool {
+	return !c.ExpiresAt.IsZero() && !time.Now().Before(c.ExpiresAt)
+}
+
 type Repository interface {
 	Store(ctx context.Context, consent *Consent) error
-	UpdateWithScopes(ctx context.Context, consent *Consent) error
+	UpdateWithGrant(ctx context.Context, consent *Consent) error
 	FindByClientAndSubject(ctx context.Context, clientID, subjectID string) (*Consent, error)
+	FindBySubject(ctx context.Context, subjectID string) ([]*Consent, error)
+	Delete(ctx context.Context, consent *Consent) error
 }
@@ -0,0 +1,61 @@
+package consent
+
+import (
+	"context"
+	"github.com/segmentio/ksuid"
+	"time"
+)
+
+// Types of consent history events.
+const (
+	// EventGranted is recorded when subject grants consent through consent provider.
+	EventGranted = "granted"
+	// EventGrantedOnce is recorded when subject grants consent for current authorization only,
+	// it lists granted scopes and details only, as remembered consent is not changed.
+	EventGrantedOnce = "granted_once"
+	// EventGrantedImplicitly is recorded when scopes or details are granted without asking subject.
+	EventGrantedImplicitly = "granted_implicitly"
+	// EventRevoked is recorded when consent is withdrawn through admin API.
+	EventRevoked = "revoked"
+)
+
+// Event is append-only record of consent change, which allows to tell what subject has consented to at any point in time.
+type Event struct {
+	ID                          string
+	Type                        string
+	ClientID                    string
+	SubjectID                   string
+	AddedScopes                 Scopes
+	RemovedScopes               Scopes
+	AddedAuthorizationDetails   AuthorizationDetails
+	RemovedAuthorizationDetails AuthorizationDetails
+	// ExpiresAt is consent expiry after the change.
+	ExpiresAt   time.Time
+	ChallengeID string
+	RequestID   string
+
+	CreatedAt time.Time
+}
+
+// NewEvent describes change of consent from previous scopes and details to the ones consent has now.
+func NewEvent(eventType string, cs *Consent, previousScopes Scopes, previousDetails AuthorizationDetails) *Event {
+	return &Event{
+		ID:                          ksuid.New().String(),
+		Type:                        eventType,
+		ClientID:                    cs.ClientID,
+		SubjectID:                   cs.SubjectID,
+		AddedScopes:                 cs.Scopes.Diff(previousScopes),
+		RemovedScopes:               previousScopes.Diff(cs.Scopes),
+		AddedAuthorizationDetails:   cs.AuthorizationDetails.Diff(previousDetails),
+		RemovedAuthorizationDetails: previousDetails.Diff(cs.AuthorizationDetails),
+		ExpiresAt:                   cs.ExpiresAt,
+		CreatedAt:                   time.Now(),
+	}
+}
+
+// HistoryRepository stores consent events, they are never updated nor deleted.
+type HistoryRepository interface {
+	Store(ctx context.Context, event *Event) error
+	// FindBySubject returns subject's events in the order they were recorded.
+	FindBySubject(ctx context.Context, subjectID string) ([]*Event, error)
+}
@@ -3,20 +3,47 @@ package consent
 import (
 	"context"
 	"github.com/damejeras/auth/api"
+	"github.com/damejeras/auth/internal/app"
+	"github.com/damejeras/auth/internal/scope"
+	"github.com/damejeras/auth/pkg/dynamo"
 	"github.com/pkg/errors"
 	"github.com/segmentio/ksuid"
 	"net/url"
+	"time"
 )
 
+// consentErrors are error codes consent provider may reject consent challenge with.
+var consentErrors = map[string]struct{}{
+	"access_denied":        {},
+	"consent_required":     {},
+	"interaction_required": {},
+	"invalid_scope":        {},
+}
+
 type consentService struct {
 	consentRepository          Repository
+	historyRepository          HistoryRepository
 	consentChallengeRepository ChallengeRepository
+	tokenStore                 dynamo.TokenStore
+	scopeRegistry              *scope.Registry
+	rememberFor                time.Duration
 }
 
-func NewService(consentRepository Repository, consentChallengeRepository ChallengeRepository) api.ConsentService {
+func NewService(
+	consentRepository Repository,
+	historyRepository HistoryRepository,
+	consentChallengeRepository ChallengeRepository,
+	tokenStore dynamo.TokenStore,
+	scopeRegistry *scope.Registry,
+	cfg *app.Config,
+) api.ConsentService {
 	return &consentService{
 		consentRepository:          consentRepository,
+		historyRepository:          historyRepository,
 		consentChallengeRepository: consentChallengeRepository,
+		tokenStore:                 tokenStore,
+		scopeRegistry:              scopeRegistry,
+		rememberFor:                cfg.ConsentProviderConfig.RememberFor,
 	}
 }
 
@@ -26,55 +53,185 @@ func (c *consentService) GrantConsent(ctx context.Context, request api.GrantCons
 		return nil, errors.Wrap(err, "find consent challenge")
 	}
 
-	if challenge == nil {
+	if challenge == nil || challenge.GrantedScopes != nil || challenge.Rejection != nil {
 		return nil, errors.Errorf("invalid consent challenge")
 	}
 
 	challenge.GrantedScopes = BuildScopes(request.Scopes)
+	if !challenge.RequestedScopes.HasAll(challenge.GrantedScopes) {
+		return nil, errors.New("scope was not requested")
+	}
+
+	// consent provider approves subset of requested details, it can not introduce details of its own
+	challenge.GrantedAuthorizationDetails = make(AuthorizationDetails, 0, len(request.AuthorizationDetails))
+	for i := range request.AuthorizationDetails {
+		detail, err := ParseAuthorizationDetail(request.AuthorizationDetails[i])
+		if err != nil {
+			return nil, errors.Wrap(err, "parse authorization detail")
+		}
+
+		if !challenge.RequestedAuthorizationDetails.Contains(detail) {
+			return nil, errors.New("authorization detail was not requested")
+		}
+
+		challenge.GrantedAuthorizationDetails = append(challenge.GrantedAuthorizationDetails, detail)
+	}
 
 	consent, err := c.consentRepository.FindByClientAndSubject(ctx, challenge.ClientID, challenge.SubjectID)
 	if err != nil {
 		return nil, errors.Wrap(err, "find consent by client and subject")
 	}
 
-	if consent != nil {
-		consent.Scopes = consent.Scopes.Merge(challenge.GrantedScopes)
-		if err := c.consentRepository.UpdateWithScopes(ctx, consent); err != nil {
-			return nil, errors.Wrap(err, "update consent with scopes")
-		}
+	var event *Event
+	if request.Remember {
+		event, err = c.rememberGrant(ctx, consent, challenge, time.Duration(request.RememberFor)*time.Second)
 	} else {
+		event, err = c.grantOnce(ctx, consent, challenge)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	event.ChallengeID = challenge.ID
+	event.RequestID = challenge.Footprint.RequestID
+
+	if err := c.historyRepository.Store(ctx, event); err != nil {
+		return nil, errors.Wrap(err, "store consent event")
+	}
+
+	if err := c.consentChallengeRepository.UpdateWithGrant(ctx, challenge); err != nil {
+		return nil, errors.Wrap(err, "update consent challenge with grant")
+	}
+
+	redirectURL, err := verifierRedirectURL(challenge)
+	if err != nil {
+		return nil, errors.Wrap(err, "build redirect url")
+	}
+
+	return &api.GrantConsentResponse{
+		RedirectURL: redirectURL,
+	}, nil
+}
+
+// rememberGrant merges granted scopes and details into subject's consent, so they are not asked for again until it expires.
+func (c *consentService) rememberGrant(ctx context.Context, consent *Consent, challenge *Challenge, rememberFor time.Duration) (*Event, error) {
+	if rememberFor <= 0 {
+		rememberFor = c.rememberFor
+	}
+
+	expiresAt := time.Now().Add(rememberFor)
+
+	if consent == nil {
 		consent = &Consent{
-			ID:        ksuid.New().String(),
-			ClientID:  challenge.ClientID,
-			SubjectID: challenge.SubjectID,
-			Scopes:    challenge.GrantedScopes,
+			ID:                   ksuid.New().String(),
+			ClientID:             challenge.ClientID,
+			SubjectID:            challenge.SubjectID,
+			Scopes:               challenge.GrantedScopes,
+			AuthorizationDetails: challenge.GrantedAuthorizationDetails,
+			ExpiresAt:            expiresAt,
 		}
 
 		if err := c.consentRepository.Store(ctx, consent); err != nil {
 			return nil, errors.Wrap(err, "store consent")
 		}
+
+		return NewEvent(EventGranted, consent, nil, nil), nil
 	}
 
-	if err := c.consentChallengeRepository.UpdateWithGrantedScopes(ctx, challenge); err != nil {
-		return nil, errors.Wrap(err, "update consent challenge granted scopes")
+	previousScopes, previousDetails := consent.Scopes, consent.AuthorizationDetails
+
+	if consent.Expired() {
+		consent.Scopes = challenge.GrantedScopes
+		consent.AuthorizationDetails = challenge.GrantedAuthorizationDetails
+	} else {
+		consent.Scopes = consent.Scopes.Merge(challenge.GrantedScopes)
+		consent.AuthorizationDetails = consent.AuthorizationDetails.Merge(challenge.GrantedAuthorizationDetails)
 	}
 
-	requestURL, err := url.Parse(challenge.Footprint.RequestURL)
-	if err != nil {
-		return nil, errors.Wrap(err, "parse request url")
+	consent.ExpiresAt = expiresAt
+
+	if err := c.consentRepository.UpdateWithGrant(ctx, consent); err != nil {
+		return nil, errors.Wrap(err, "update consent with grant")
 	}
 
-	urlValues, err := url.ParseQuery(requestURL.RawQuery)
+	return NewEvent(EventGranted, consent, previousScopes, previousDetails), nil
+}
+
+// grantOnce records consent, which applies to current authorization only. Remembered consent is left intact,
+// granted details are tracked apart from it, because token endpoint reads approved details from consent.
+func (c *consentService) grantOnce(ctx context.Context, consent *Consent, challenge *Challenge) (*Event, error) {
+	// event describes one-off grant as consent, which expires as soon as it is given
+	once := &Consent{
+		ClientID:             challenge.ClientID,
+		SubjectID:            challenge.SubjectID,
+		Scopes:               challenge.GrantedScopes,
+		AuthorizationDetails: challenge.GrantedAuthorizationDetails,
+		ExpiresAt:            time.Now(),
+	}
+
+	if consent == nil {
+		consent = once
+		consent.ID = ksuid.New().String()
+		consent.OnceAuthorizationDetails = challenge.GrantedAuthorizationDetails
+
+		if err := c.consentRepository.Store(ctx, consent); err != nil {
+			return nil, errors.Wrap(err, "store consent")
+		}
+
+		return NewEvent(EventGrantedOnce, consent, nil, nil), nil
+	}
+
+	if consent.Expired() {
+		consent.Scopes = challenge.GrantedScopes
+		consent.AuthorizationDetails = challenge.GrantedAuthorizationDetails
+		consent.ExpiresAt = once.ExpiresAt
+	}
+
+	consent.OnceAuthorizationDetails = challenge.GrantedAuthorizationDetails
+
+	if err := c.consentRepository.UpdateWithGrant(ctx, consent); err != nil {
+		return nil, errors.Wrap(err, "update consent with grant")
+	}
+
+	// remembered scopes and details are kept, so they are not recorded as removed
+	return NewEvent(EventGrantedOnce, once, nil, nil), nil
+}
+
+func (c *consentService) RejectConsent(ctx context.Context, request api.RejectConsentRequest) (*api.RejectConsentResponse, error) {
+	if request.Error == "" {
+		request.Error = "access_denied"
+	}
+
+	if _, ok := consentErrors[request.Error]; !ok {
+		return nil, errors.Errorf("unsupported error %q", request.Error)
+	}
+
+	challenge, err := c.consentChallengeRepository.FindByID(ctx, request.ChallengeID)
 	if err != nil {
-		return nil, errors.Wrap(err, "parse url values")
+		return nil, errors.Wrap(err, "find consent challenge")
 	}
 
-	urlValues.Add("consent_verifier", challenge.Verifier)
+	if challenge == nil || challenge.GrantedScopes != nil || challenge.Rejection != nil {
+		return nil, errors.Errorf("invalid consent challenge")
+	}
 
-	requestURL.RawQuery = urlValues.Encode()
+	challenge.Rejection = &Rejection{
+		Error:       request.Error,
+		Description: request.ErrorDescription,
+	}
 
-	return &api.GrantConsentResponse{
-		RedirectURL: requestURL.String(),
+	if err := c.consentChallengeRepository.UpdateWithRejection(ctx, challenge); err != nil {
+		return nil, errors.Wrap(err, "update consent challenge rejection")
+	}
+
+	redirectURL, err := verifierRedirectURL(challenge)
+	if err != nil {
+		return nil, errors.Wrap(err, "build redirect url")
+	}
+
+	return &api.RejectConsentResponse{
+		RedirectURL: redirectURL,
 	}, nil
 }
 
@@ -84,10 +241,187 @@ func (c *consentService) ShowConsentChallenge(ctx context.Context, request api.S
 		return nil, errors.Wrap(err, "find consent challenge")
 	}
 
+	if challenge == nil {
+		return nil, errors.New("invalid consent challenge")
+	}
+
+	requestedScopes, err := c.scopeRegistry.Resolve(ctx, challenge.RequestedScopes.ToSlice())
+	if err != nil {
+		return nil, errors.Wrap(err, "resolve requested scopes")
+	}
+
+	scopeMetadata := make([]api.RequestedScope, len(requestedScopes))
+	for i := range requestedScopes {
+		scopeMetadata[i] = scope.RequestedToAPI(requestedScopes[i])
+	}
+
 	return &api.ShowConsentChallengeResponse{
-		ClientID:        challenge.ID,
-		SubjectID:       challenge.SubjectID,
-		RequestedScopes: challenge.RequestedScopes.ToSlice(),
-		MissingScopes:   challenge.MissingScopes.ToSlice(),
+		ClientID:                      challenge.ClientID,
+		SubjectID:                     challenge.SubjectID,
+		RequestedScopes:               challenge.RequestedScopes.ToSlice(),
+		MissingScopes:                 challenge.MissingScopes.ToSlice(),
+		RequestedAuthorizationDetails: challenge.RequestedAuthorizationDetails.ToSlice(),
+		MissingAuthorizationDetails:   challenge.MissingAuthorizationDetails.ToSlice(),
+		RequestedScopeMetadata:        scopeMetadata,
+	}, nil
+}
+
+func (c *consentService) ListConsentsBySubject(ctx context.Context, request api.ListConsentsBySubjectRequest) (*api.ListConsentsBySubjectResponse, error) {
+	consents, err := c.consentRepository.FindBySubject(ctx, request.SubjectID)
+	if err != nil {
+		return nil, errors.Wrap(err, "find consents by subject")
+	}
+
+	response := api.ListConsentsBySubjectResponse{
+		Consents: make([]api.Consent, len(consents)),
+	}
+
+	for i := range consents {
+		response.Consents[i] = consentToAPI(consents[i])
+	}
+
+	return &response, nil
+}
+
+// RevokeConsent withdraws subject's consent given to the client, tokens issued to the client on behalf of subject are revoked too.
+func (c *consentService) RevokeConsent(ctx context.Context, request api.RevokeConsentRequest) (*api.RevokeConsentResponse, error) {
+	consent, err := c.findConsent(ctx, request.ClientID, request.SubjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.consentRepository.Delete(ctx, consent); err != nil {
+		return nil, errors.Wrap(err, "delete consent")
+	}
+
+	previousScopes, previousDetails := consent.Scopes, consent.AuthorizationDetails
+	consent.Scopes, consent.AuthorizationDetails = make(Scopes), make(AuthorizationDetails, 0)
+
+	if err := c.historyRepository.Store(ctx, NewEvent(EventRevoked, consent, previousScopes, previousDetails)); err != nil {
+		return nil, errors.Wrap(err, "store consent event")
+	}
+
+	if err := c.tokenStore.RemoveByClientAndUser(ctx, consent.ClientID, consent.SubjectID); err != nil {
+		return nil, errors.Wrap(err, "remove tokens")
+	}
+
+	return &api.RevokeConsentResponse{}, nil
+}
+
+// RevokeScopes withdraws part of subject's consent. Issued tokens can not be narrowed, so all of them are revoked
+// and client has to obtain new ones for the scopes which are still consented to.
+func (c *consentService) RevokeScopes(ctx context.Context, request api.RevokeScopesRequest) (*api.RevokeScopesResponse, error) {
+	consent, err := c.findConsent(ctx, request.ClientID, request.SubjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	previousScopes := consent.Scopes
+	consent.Scopes = consent.Scopes.Diff(BuildScopes(request.Scopes))
+
+	if len(consent.Scopes) == 0 && len(consent.AuthorizationDetails) == 0 {
+		if err := c.consentRepository.Delete(ctx, consent); err != nil {
+			return nil, errors.Wrap(err, "delete consent")
+		}
+	} else if err := c.consentRepository.UpdateWithGrant(ctx, consent); err != nil {
+		return nil, errors.Wrap(err, "update consent with grant")
+	}
+
+	if err := c.historyRepository.Store(ctx, NewEvent(EventRevoked, consent, previousScopes, consent.AuthorizationDetails)); err != nil {
+		return nil, errors.Wrap(err, "store consent event")
+	}
+
+	if err := c.tokenStore.RemoveByClientAndUser(ctx, consent.ClientID, consent.SubjectID); err != nil {
+		return nil, errors.Wrap(err, "remove tokens")
+	}
+
+	return &api.RevokeScopesResponse{
+		Consent: consentToAPI(consent),
 	}, nil
 }
+
+// ListConsentHistory returns subject's consent events in the order they were recorded, optionally for single client only.
+func (c *consentService) ListConsentHistory(ctx context.Context, request api.ListConsentHistoryRequest) (*api.ListConsentHistoryResponse, error) {
+	events, err := c.historyRepository.FindBySubject(ctx, request.SubjectID)
+	if err != nil {
+		return nil, errors.Wrap(err, "find consent events by subject")
+	}
+
+	response := api.ListConsentHistoryResponse{
+		Events: make([]api.ConsentEvent, 0, len(events)),
+	}
+
+	for i := range events {
+		if request.ClientID != "" && events[i].ClientID != request.ClientID {
+			continue
+		}
+
+		response.Events = append(response.Events, api.ConsentEvent{
+			Type:                        events[i].Type,
+			ClientID:                    events[i].ClientID,
+			SubjectID:                   events[i].SubjectID,
+			AddedScopes:                 events[i].AddedScopes.ToSlice(),
+			RemovedScopes:               events[i].RemovedScopes.ToSlice(),
+			AddedAuthorizationDetails:   events[i].AddedAuthorizationDetails.ToSlice(),
+			RemovedAuthorizationDetails: events[i].RemovedAuthorizationDetails.ToSlice(),
+			ExpiresAt:                   expiresAtUnix(events[i].ExpiresAt),
+			ChallengeID:                 events[i].ChallengeID,
+			RequestID:                   events[i].RequestID,
+			CreatedAt:                   events[i].CreatedAt.Unix(),
+		})
+	}
+
+	return &response, nil
+}
+
+func (c *consentService) findConsent(ctx context.Context, clientID, subjectID string) (*Consent, error) {
+	consent, err := c.consentRepository.FindByClientAndSubject(ctx, clientID, subjectID)
+	if err != nil {
+		return nil, errors.Wrap(err, "find consent by client and subject")
+	}
+
+	if consent == nil {
+		return nil, errors.New("consent not found")
+	}
+
+	return consent, nil
+}
+
+func consentToAPI(consent *Consent) api.Consent {
+	return api.Consent{
+		ClientID:             consent.ClientID,
+		SubjectID:            consent.SubjectID,
+		Scopes:               consent.Scopes.ToSlice(),
+		AuthorizationDetails: consent.AuthorizationDetails.ToSlice(),
+		ExpiresAt:            expiresAtUnix(consent.ExpiresAt),
+		CreatedAt:            consent.CreatedAt.Unix(),
+		UpdatedAt:            consent.UpdatedAt.Unix(),
+	}
+}
+
+// expiresAtUnix returns zero for consents, which never expire.
+func expiresAtUnix(expiresAt time.Time) int64 {
+	if expiresAt.IsZero() {
+		return 0
+	}
+
+	return expiresAt.Unix()
+}
+
+func verifierRedirectURL(challenge *Challenge) (string, error) {
+	requestURL, err := url.Parse(challenge.Footprint.RequestURL)
+	if err != nil {
+		return "", errors.Wrap(err, "parse request url")
+	}
+
+	urlValues, err := url.ParseQuery(requestURL.RawQuery)
+	if err != nil {
+		return "", errors.Wrap(err, "parse url values")
+	}
+
+	urlValues.Add("consent_verifier", challenge.Verifier)
+
+	requestURL.RawQuery = urlValues.Encode()
+
+	return requestURL.String(), nil
+}
@@ -0,0 +1,64 @@
+package device
+
+import (
+	"context"
+	"time"
+)
+
+// Authorization is pending device authorization request. Device polls token endpoint with DeviceCode,
+// while user enters UserCode on verification page and approves request in the browser.
+type Authorization struct {
+	DeviceCode string
+	UserCode   string
+	ClientID   string
+	Scope      string
+	SubjectID  string
+	Denied     bool
+	// Confirmation is set once user has passed login and consent, request is approved only after user confirms it.
+	Confirmation *Confirmation
+	Interval     time.Duration
+	PolledAt     time.Time
+	ExpiresAt    time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Confirmation is subject and scope request is approved for, once the subject confirms it on verification page.
+type Confirmation struct {
+	SubjectID string
+	Scope     string
+}
+
+func (a *Authorization) Expired() bool {
+	return time.Now().After(a.ExpiresAt)
+}
+
+// Approved reports whether user has approved the request.
+func (a *Authorization) Approved() bool {
+	return a.SubjectID != ""
+}
+
+// Pending reports whether user has neither approved nor denied the request yet.
+func (a *Authorization) Pending() bool {
+	return !a.Approved() && !a.Denied
+}
+
+type Repository interface {
+	Store(context.Context, *Authorization) error
+	UpdateWithConfirmation(context.Context, *Authorization) error
+	UpdateWithApproval(context.Context, *Authorization) error
+	UpdateWithDenial(context.Context, *Authorization) error
+	UpdateWithPoll(context.Context, *Authorization) error
+	FindByDeviceCode(context.Context, string) (*Authorization, error)
+	FindByUserCode(context.Context, string) (*Authorization, error)
+	Delete(context.Context, *Authorization) error
+}
+
+// GuessRepository counts unknown user codes entered from source IP address, so user codes can not be brute forced.
+type GuessRepository interface {
+	// Count returns number of guesses made from IP address within the window starting at given time.
+	Count(ctx context.Context, ipAddress string, window time.Time) (int, error)
+	// Add records guess made from IP address within the window, which lasts until expiresAt.
+	Add(ctx context.Context, ipAddress string, window, expiresAt time.Time) error
+}
@@ -0,0 +1,25 @@
+package device
+
+import (
+	stdErrors "errors"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"net/http"
+)
+
+// Token endpoint errors defined by RFC 8628 section 3.5.
+var (
+	ErrAuthorizationPending = stdErrors.New("authorization_pending")
+	ErrSlowDown             = stdErrors.New("slow_down")
+	ErrExpiredToken         = stdErrors.New("expired_token")
+)
+
+// register errors with oauth2 server, so they are rendered as regular token endpoint errors.
+func init() {
+	errors.Descriptions[ErrAuthorizationPending] = "The authorization request is still pending as the end user hasn't yet completed the user-interaction steps"
+	errors.Descriptions[ErrSlowDown] = "The authorization request is still pending and polling should continue, but the interval must be increased"
+	errors.Descriptions[ErrExpiredToken] = "The device_code has expired, and the device authorization session has concluded"
+
+	errors.StatusCodes[ErrAuthorizationPending] = http.StatusBadRequest
+	errors.StatusCodes[ErrSlowDown] = http.StatusBadRequest
+	errors.StatusCodes[ErrExpiredToken] = http.StatusBadRequest
+}
@@ -0,0 +1,560 @@
+package device
+
+import (
+	"crypto/rand"
+	"github.com/damejeras/auth/internal/app"
+	"github.com/damejeras/auth/internal/client"
+	"github.com/damejeras/auth/internal/identity"
+	"github.com/damejeras/auth/internal/scope"
+	"github.com/damejeras/auth/internal/session"
+	"github.com/damejeras/auth/pkg/dynamo"
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/manage"
+	"github.com/go-oauth2/oauth2/v4/server"
+	pkgErrors "github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/segmentio/ksuid"
+	"html/template"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// GrantType is device authorization grant type defined by RFC 8628.
+const GrantType oauth2.GrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+const (
+	paramUserCode   = "user_code"
+	paramDeviceCode = "device_code"
+	paramCSRFToken  = "csrf_token"
+	paramDecision   = "decision"
+
+	decisionApprove = "approve"
+
+	// user codes consist of consonants only, so they are easy to type and do not form words.
+	userCodeCharset = "BCDFGHJKLMNPQRSTVWXZ"
+	userCodeLength  = 8
+
+	slowDownIncrement = 5 * time.Second
+)
+
+var verificationPage = template.Must(template.New("verification").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Device activation</title></head>
+<body>
+{{if .Message}}<p>{{.Message}}</p>
+{{else}}<form method="get">
+{{if .Error}}<p>{{.Error}}</p>
+{{end}}<label for="user_code">Enter the code displayed on your device</label>
+<input id="user_code" name="user_code" autocomplete="off" autofocus>
+<button type="submit">Continue</button>
+</form>
+{{end}}</body>
+</html>
+`))
+
+type verificationPageData struct {
+	Message string
+	Error   string
+}
+
+// confirmationPage shows user which client is going to be authorized and for what, so codes from other people
+// can not be approved unknowingly.
+var confirmationPage = template.Must(template.New("confirmation").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Device activation</title></head>
+<body>
+<form method="post" action="{{.Action}}">
+<p>Code {{.UserCode}} was entered. Do you want to authorize {{.ClientID}}{{if .ClientDomain}} ({{.ClientDomain}}){{end}} on your device?</p>
+{{if .Scopes}}<p>The device will be allowed to:</p>
+<ul>
+{{range .Scopes}}<li>{{.}}</li>
+{{end}}</ul>
+{{end}}<p>Do not continue if you did not start this on your own device.</p>
+<input type="hidden" name="user_code" value="{{.UserCode}}">
+<input type="hidden" name="csrf_token" value="{{.CSRFToken}}">
+<button type="submit" name="decision" value="approve">Authorize</button>
+<button type="submit" name="decision" value="deny">Deny</button>
+</form>
+</body>
+</html>
+`))
+
+type confirmationPageData struct {
+	Action       string
+	UserCode     string
+	ClientID     string
+	ClientDomain string
+	Scopes       []string
+	CSRFToken    string
+}
+
+// Handler serves device authorization endpoint, user verification page and device code grant.
+type Handler struct {
+	repository               Repository
+	guessRepository          GuessRepository
+	clientStorage            *client.Storage
+	registry                 *scope.Registry
+	sessionRepository        session.Repository
+	sessionCookie            *session.Cookie
+	userAuthorizationHandler server.UserAuthorizationHandler
+	verificationURI          string
+	lifetime                 time.Duration
+	interval                 time.Duration
+	guesses                  int
+	guessWindow              time.Duration
+	logger                   *zerolog.Logger
+}
+
+func NewHandler(
+	repository Repository,
+	guessRepository GuessRepository,
+	clientStorage *client.Storage,
+	registry *scope.Registry,
+	sessionRepository session.Repository,
+	sessionCookie *session.Cookie,
+	identityManager *identity.Manager,
+	cfg *app.Config,
+	logger *zerolog.Logger,
+) *Handler {
+	return &Handler{
+		repository:               repository,
+		guessRepository:          guessRepository,
+		clientStorage:            clientStorage,
+		registry:                 registry,
+		sessionRepository:        sessionRepository,
+		sessionCookie:            sessionCookie,
+		userAuthorizationHandler: identityManager.UserAuthorizationHandler(),
+		verificationURI:          strings.TrimSuffix(cfg.Oauth2Config.Issuer, "/") + "/device",
+		lifetime:                 cfg.DeviceConfig.Lifetime,
+		interval:                 cfg.DeviceConfig.Interval,
+		guesses:                  cfg.DeviceConfig.Guesses,
+		guessWindow:              cfg.DeviceConfig.GuessWindow,
+		logger:                   logger,
+	}
+}
+
+// HandleDeviceAuthorizationRequest issues device and user codes to authenticated client.
+func (h *Handler) HandleDeviceAuthorizationRequest(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodPost {
+		return app.WriteError(w, errors.ErrInvalidRequest)
+	}
+
+	clientID, clientSecret, err := server.ClientBasicHandler(r)
+	if err != nil {
+		return app.WriteError(w, err)
+	}
+
+	cl, err := h.clientStorage.FindByID(r.Context(), clientID)
+	if err != nil {
+		app.WriteError(w, errors.ErrServerError)
+
+		return pkgErrors.Wrapf(err, "find client %q", clientID)
+	}
+
+	if cl == nil || cl.Secret != clientSecret {
+		return app.WriteError(w, errors.ErrInvalidClient)
+	}
+
+	// device authorization requests do not pass authorization endpoint middlewares, which reject unknown scopes
+	_, err = h.registry.Resolve(r.Context(), strings.Fields(r.FormValue("scope")))
+	switch {
+	case err == errors.ErrInvalidScope:
+		return app.WriteError(w, err)
+	case err != nil:
+		app.WriteError(w, errors.ErrServerError)
+
+		return pkgErrors.Wrap(err, "resolve requested scopes")
+	}
+
+	userCode, err := generateUserCode()
+	if err != nil {
+		app.WriteError(w, errors.ErrServerError)
+
+		return pkgErrors.Wrap(err, "generate user code")
+	}
+
+	now := time.Now()
+
+	authorization := Authorization{
+		DeviceCode: ksuid.New().String(),
+		UserCode:   userCode,
+		ClientID:   cl.ID,
+		Scope:      r.FormValue("scope"),
+		Interval:   h.interval,
+		ExpiresAt:  now.Add(h.lifetime),
+		CreatedAt:  now,
+	}
+
+	if err := h.repository.Store(r.Context(), &authorization); err != nil {
+		app.WriteError(w, errors.ErrServerError)
+
+		return pkgErrors.Wrap(err, "store device authorization")
+	}
+
+	verificationURIComplete, err := url.Parse(h.verificationURI)
+	if err != nil {
+		app.WriteError(w, errors.ErrServerError)
+
+		return pkgErrors.Wrap(err, "parse verification uri")
+	}
+
+	verificationURIComplete.RawQuery = url.Values{paramUserCode: {userCode}}.Encode()
+
+	return app.WriteJSON(w, http.StatusOK, map[string]interface{}{
+		"device_code":               authorization.DeviceCode,
+		"user_code":                 authorization.UserCode,
+		"verification_uri":          h.verificationURI,
+		"verification_uri_complete": verificationURIComplete.String(),
+		"expires_in":                int64(h.lifetime / time.Second),
+		"interval":                  int64(h.interval / time.Second),
+	})
+}
+
+// HandleVerificationRequest asks user for the code displayed on device and authorizes the request
+// through the same login and consent challenges as authorization endpoint does. Authorized request
+// is approved only after user confirms it, see HandleConfirmationRequest.
+func (h *Handler) HandleVerificationRequest(w http.ResponseWriter, r *http.Request) error {
+	if r.Method == http.MethodPost {
+		return h.HandleConfirmationRequest(w, r)
+	}
+
+	query := r.URL.Query()
+
+	userCode := normalizeUserCode(query.Get(paramUserCode))
+	if userCode == "" {
+		return renderVerificationPage(w, http.StatusOK, verificationPageData{})
+	}
+
+	authorization, err := h.findByUserCode(w, r, userCode)
+	if err != nil || authorization == nil {
+		return err
+	}
+
+	if authorization.Confirmation != nil {
+		sess, err := session.FromRequest(r, h.sessionCookie, h.sessionRepository)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+			return pkgErrors.Wrap(err, "find current session")
+		}
+
+		// confirmation is shown only to the user, who has passed login and consent in this user agent
+		if sess != nil && sess.SubjectID == authorization.Confirmation.SubjectID {
+			return h.confirm(w, r, sess, authorization)
+		}
+	}
+
+	// login and consent challenges read authorization parameters from request URL
+	if query.Get(paramUserCode) != authorization.UserCode ||
+		query.Get("client_id") != authorization.ClientID ||
+		query.Get("scope") != authorization.Scope {
+		location := url.URL{
+			Path: r.URL.Path,
+			RawQuery: url.Values{
+				paramUserCode: {authorization.UserCode},
+				"client_id":   {authorization.ClientID},
+				"scope":       {authorization.Scope},
+			}.Encode(),
+		}
+
+		w.Header().Set("Location", location.String())
+		w.WriteHeader(http.StatusFound)
+
+		return nil
+	}
+
+	subjectID, err := h.userAuthorizationHandler(w, r)
+	switch {
+	case err == errors.ErrServerError:
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+		return pkgErrors.Wrap(err, "authorize user")
+	case err == errors.ErrInvalidRequest:
+		return renderVerificationPage(w, http.StatusOK, verificationPageData{Error: "The request is invalid, please enter the code again."})
+	case identity.Rejected(err):
+		return h.deny(w, r, authorization)
+	case err != nil:
+		// request is left pending, as it was not rejected by user, e.g. subject is locked out
+		h.logger.Warn().Err(err).Msgf("authorize device of client %q", authorization.ClientID)
+
+		return renderVerificationPage(w, http.StatusOK, verificationPageData{Message: "The device could not be authorized, please try again later."})
+	case subjectID == "":
+		// user agent was redirected to login or consent provider
+		return nil
+	}
+
+	authorization.Confirmation = &Confirmation{
+		SubjectID: subjectID,
+		// identity manager narrows requested scope to the one subject has consented to
+		Scope: r.FormValue("scope"),
+	}
+
+	if err := h.repository.UpdateWithConfirmation(r.Context(), authorization); err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+		return pkgErrors.Wrap(err, "update device authorization with confirmation")
+	}
+
+	// session cookie might have been just written, so confirmation is rendered by the next request
+	location := url.URL{
+		Path:     r.URL.Path,
+		RawQuery: url.Values{paramUserCode: {authorization.UserCode}}.Encode(),
+	}
+
+	w.Header().Set("Location", location.String())
+	w.WriteHeader(http.StatusFound)
+
+	return nil
+}
+
+// HandleConfirmationRequest approves or denies authorization confirmed by the same user, who has passed
+// login and consent. Form has to carry CSRF token of user session, so it can not be submitted cross-site.
+func (h *Handler) HandleConfirmationRequest(w http.ResponseWriter, r *http.Request) error {
+	userCode := normalizeUserCode(r.PostFormValue(paramUserCode))
+	if userCode == "" {
+		return renderVerificationPage(w, http.StatusOK, verificationPageData{})
+	}
+
+	authorization, err := h.findByUserCode(w, r, userCode)
+	if err != nil || authorization == nil {
+		return err
+	}
+
+	sess, err := session.FromRequest(r, h.sessionCookie, h.sessionRepository)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+		return pkgErrors.Wrap(err, "find current session")
+	}
+
+	if authorization.Confirmation == nil || sess == nil ||
+		sess.SubjectID != authorization.Confirmation.SubjectID ||
+		!h.sessionCookie.VerifyCSRFToken(sess, r.PostFormValue(paramCSRFToken)) {
+		return renderVerificationPage(w, http.StatusBadRequest, verificationPageData{Error: "The request is invalid, please enter the code again."})
+	}
+
+	if r.PostFormValue(paramDecision) != decisionApprove {
+		return h.deny(w, r, authorization)
+	}
+
+	authorization.SubjectID = authorization.Confirmation.SubjectID
+	authorization.Scope = authorization.Confirmation.Scope
+
+	if err := h.repository.UpdateWithApproval(r.Context(), authorization); err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+		return pkgErrors.Wrap(err, "update device authorization with approval")
+	}
+
+	return renderVerificationPage(w, http.StatusOK, verificationPageData{Message: "The device has been authorized. You can return to your device."})
+}
+
+// findByUserCode returns pending authorization by user code. Unknown codes are counted per IP address and
+// once too many of them are entered, further codes are not looked up until the window passes.
+// Nil authorization is returned, when response has been written already.
+func (h *Handler) findByUserCode(w http.ResponseWriter, r *http.Request, userCode string) (*Authorization, error) {
+	ipAddress := app.RemoteIP(r)
+	window := time.Now().Truncate(h.guessWindow)
+
+	guesses, err := h.guessRepository.Count(r.Context(), ipAddress, window)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+		return nil, pkgErrors.Wrap(err, "count user code guesses")
+	}
+
+	if guesses >= h.guesses {
+		return nil, renderVerificationPage(w, http.StatusTooManyRequests, verificationPageData{Message: "Too many invalid codes were entered, please try again later."})
+	}
+
+	authorization, err := h.repository.FindByUserCode(r.Context(), userCode)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+		return nil, pkgErrors.Wrap(err, "find device authorization by user code")
+	}
+
+	if authorization == nil {
+		if err := h.guessRepository.Add(r.Context(), ipAddress, window, window.Add(h.guessWindow)); err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+			return nil, pkgErrors.Wrap(err, "add user code guess")
+		}
+	}
+
+	if authorization == nil || authorization.Expired() || !authorization.Pending() {
+		return nil, renderVerificationPage(w, http.StatusOK, verificationPageData{Error: "The code is invalid or has expired."})
+	}
+
+	return authorization, nil
+}
+
+// confirm renders confirmation form with client and scopes authorization is going to be approved for.
+func (h *Handler) confirm(w http.ResponseWriter, r *http.Request, sess *session.Session, authorization *Authorization) error {
+	cl, err := h.clientStorage.FindByID(r.Context(), authorization.ClientID)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+		return pkgErrors.Wrapf(err, "find client %q", authorization.ClientID)
+	}
+
+	matches, err := h.registry.Resolve(r.Context(), strings.Fields(authorization.Confirmation.Scope))
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+		return pkgErrors.Wrap(err, "resolve confirmed scopes")
+	}
+
+	data := confirmationPageData{
+		Action:    r.URL.Path,
+		UserCode:  authorization.UserCode,
+		ClientID:  authorization.ClientID,
+		CSRFToken: h.sessionCookie.CSRFToken(sess),
+	}
+
+	if cl != nil {
+		data.ClientDomain = cl.Domain
+	}
+
+	for i := range matches {
+		if matches[i].Scope.DisplayName != "" {
+			data.Scopes = append(data.Scopes, matches[i].Scope.DisplayName)
+		} else {
+			data.Scopes = append(data.Scopes, matches[i].Value)
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Frame-Options", "DENY")
+
+	return confirmationPage.Execute(w, data)
+}
+
+func (h *Handler) deny(w http.ResponseWriter, r *http.Request, authorization *Authorization) error {
+	authorization.Denied = true
+
+	if err := h.repository.UpdateWithDenial(r.Context(), authorization); err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+		return pkgErrors.Wrap(err, "update device authorization with denial")
+	}
+
+	return renderVerificationPage(w, http.StatusOK, verificationPageData{Message: "The device has not been authorized."})
+}
+
+// TokenConfig returns the same token configuration as authorization code grant has, as both are used by end users.
+func (h *Handler) TokenConfig() *manage.Config {
+	return manage.DefaultAuthorizeCodeTokenCfg
+}
+
+// ResolveTokenRequest resolves device code grant. Pending authorizations are answered with polling errors,
+// approved authorization is consumed and its subject and scope are used for the token.
+func (h *Handler) ResolveTokenRequest(tgr *oauth2.TokenGenerateRequest, _ *dynamo.Token) error {
+	ctx := tgr.Request.Context()
+
+	deviceCode := tgr.Request.FormValue(paramDeviceCode)
+	if deviceCode == "" {
+		return errors.ErrInvalidRequest
+	}
+
+	authorization, err := h.repository.FindByDeviceCode(ctx, deviceCode)
+	if err != nil {
+		return pkgErrors.Wrap(err, "find device authorization by device code")
+	}
+
+	if authorization == nil || authorization.ClientID != tgr.ClientID {
+		return errors.ErrInvalidGrant
+	}
+
+	switch {
+	case authorization.Expired():
+		if err := h.repository.Delete(ctx, authorization); err != nil {
+			return pkgErrors.Wrap(err, "delete device authorization")
+		}
+
+		return ErrExpiredToken
+	case authorization.Denied:
+		if err := h.repository.Delete(ctx, authorization); err != nil {
+			return pkgErrors.Wrap(err, "delete device authorization")
+		}
+
+		return errors.ErrAccessDenied
+	case authorization.Pending():
+		pollErr := ErrAuthorizationPending
+
+		now := time.Now()
+		if now.Sub(authorization.PolledAt) < authorization.Interval {
+			authorization.Interval += slowDownIncrement
+			pollErr = ErrSlowDown
+		}
+
+		authorization.PolledAt = now
+
+		if err := h.repository.UpdateWithPoll(ctx, authorization); err != nil {
+			return pkgErrors.Wrap(err, "update device authorization with poll")
+		}
+
+		return pollErr
+	}
+
+	if err := h.repository.Delete(ctx, authorization); err != nil {
+		return pkgErrors.Wrap(err, "delete device authorization")
+	}
+
+	tgr.UserID = authorization.SubjectID
+	tgr.Scope = authorization.Scope
+
+	return nil
+}
+
+func generateUserCode() (string, error) {
+	var sb strings.Builder
+
+	max := big.NewInt(int64(len(userCodeCharset)))
+	for i := 0; i < userCodeLength; i++ {
+		if i == userCodeLength/2 {
+			sb.WriteByte('-')
+		}
+
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+
+		sb.WriteByte(userCodeCharset[n.Int64()])
+	}
+
+	return sb.String(), nil
+}
+
+// normalizeUserCode converts user input to the format user codes are stored in,
+// so codes can be typed in lower case and without separator.
+func normalizeUserCode(input string) string {
+	var sb strings.Builder
+
+	for _, r := range strings.ToUpper(input) {
+		if strings.ContainsRune(userCodeCharset, r) {
+			sb.WriteRune(r)
+		}
+	}
+
+	code := sb.String()
+	if len(code) != userCodeLength {
+		return code
+	}
+
+	return code[:userCodeLength/2] + "-" + code[userCodeLength/2:]
+}
+
+func renderVerificationPage(w http.ResponseWriter, status int, data verificationPageData) error {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+
+	return verificationPage.Execute(w, data)
+}
@@ -0,0 +1,17 @@
+package dpop
+
+import (
+	stdErrors "errors"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"net/http"
+)
+
+// ErrInvalidDPoPProof is token endpoint error defined by RFC 9449 section 5.
+var ErrInvalidDPoPProof = stdErrors.New("invalid_dpop_proof")
+
+// register error with oauth2 server, so it is rendered as regular token endpoint error.
+func init() {
+	errors.Descriptions[ErrInvalidDPoPProof] = "The DPoP proof is invalid"
+
+	errors.StatusCodes[ErrInvalidDPoPProof] = http.StatusBadRequest
+}
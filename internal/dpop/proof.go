@@ -0,0 +1,24 @@
+package dpop
+
+import (
+	"context"
+	stdErrors "errors"
+	"time"
+)
+
+// ErrProofReplayed is returned by repository, when proof with the same ID has already been stored.
+var ErrProofReplayed = stdErrors.New("proof is replayed")
+
+// Proof is used DPoP proof. Proofs are remembered until they expire, so each of them is accepted only once.
+// Repository is expected to evict expired proofs.
+type Proof struct {
+	ID            string
+	KeyThumbprint string
+	ExpiresAt     time.Time
+
+	CreatedAt time.Time
+}
+
+type Repository interface {
+	Store(context.Context, *Proof) error
+}
@@ -0,0 +1,163 @@
+package dpop
+
+import (
+	"encoding/json"
+	"github.com/damejeras/auth/internal/app"
+	"github.com/damejeras/auth/internal/signing"
+	"github.com/golang-jwt/jwt"
+	pkgErrors "github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// HeaderProof is request header carrying DPoP proof.
+	HeaderProof = "DPoP"
+	// TokenType is type of access tokens bound to proof key.
+	TokenType = "DPoP"
+	// ClaimConfirmation is token claim holding key binding, ClaimThumbprint is its member with key thumbprint.
+	ClaimConfirmation = "cnf"
+	ClaimThumbprint   = "jkt"
+
+	proofType = "dpop+jwt"
+)
+
+var validMethods = []string{
+	jwt.SigningMethodRS256.Alg(),
+	jwt.SigningMethodRS384.Alg(),
+	jwt.SigningMethodRS512.Alg(),
+	jwt.SigningMethodPS256.Alg(),
+	jwt.SigningMethodPS384.Alg(),
+	jwt.SigningMethodPS512.Alg(),
+	jwt.SigningMethodES256.Alg(),
+	jwt.SigningMethodES384.Alg(),
+	jwt.SigningMethodES512.Alg(),
+}
+
+// Verifier validates DPoP proofs defined by RFC 9449 and remembers them, so they can not be replayed.
+type Verifier struct {
+	repository Repository
+	issuer     string
+	lifetime   time.Duration
+	logger     *zerolog.Logger
+}
+
+func NewVerifier(repository Repository, cfg *app.Config, logger *zerolog.Logger) *Verifier {
+	return &Verifier{
+		repository: repository,
+		issuer:     strings.TrimSuffix(cfg.Oauth2Config.Issuer, "/"),
+		lifetime:   cfg.DPoPConfig.ProofLifetime,
+		logger:     logger,
+	}
+}
+
+// Verify validates proof presented with request and returns thumbprint of the key proof was signed with.
+// Empty thumbprint is returned, when request does not have a proof.
+func (v *Verifier) Verify(r *http.Request) (string, error) {
+	proofs := r.Header.Values(HeaderProof)
+	if len(proofs) == 0 {
+		return "", nil
+	}
+
+	if len(proofs) > 1 {
+		return "", ErrInvalidDPoPProof
+	}
+
+	claims, key, err := v.parse(r, proofs[0])
+	if err != nil {
+		v.logger.Debug().Err(err).Msg("invalid DPoP proof")
+
+		return "", ErrInvalidDPoPProof
+	}
+
+	thumbprint, err := key.Thumbprint()
+	if err != nil {
+		return "", ErrInvalidDPoPProof
+	}
+
+	issuedAt := time.Unix(int64(claims["iat"].(float64)), 0)
+
+	if err := v.repository.Store(r.Context(), &Proof{
+		ID:            claims["jti"].(string),
+		KeyThumbprint: thumbprint,
+		ExpiresAt:     issuedAt.Add(v.lifetime),
+	}); err != nil {
+		if err == ErrProofReplayed {
+			return "", ErrInvalidDPoPProof
+		}
+
+		return "", pkgErrors.Wrap(err, "store proof")
+	}
+
+	return thumbprint, nil
+}
+
+// parse checks proof signature with public key from its header and validates claims required by RFC 9449 section 4.3.
+func (v *Verifier) parse(r *http.Request, proof string) (jwt.MapClaims, *signing.JSONWebKey, error) {
+	var key signing.JSONWebKey
+
+	claims := make(jwt.MapClaims)
+	parser := jwt.Parser{
+		ValidMethods:         validMethods,
+		SkipClaimsValidation: true,
+	}
+
+	if _, err := parser.ParseWithClaims(proof, claims, func(token *jwt.Token) (interface{}, error) {
+		if typ, _ := token.Header["typ"].(string); typ != proofType {
+			return nil, pkgErrors.Errorf("unexpected type %q", typ)
+		}
+
+		keyBytes, err := json.Marshal(token.Header["jwk"])
+		if err != nil {
+			return nil, pkgErrors.Wrap(err, "marshal key")
+		}
+
+		if err := json.Unmarshal(keyBytes, &key); err != nil {
+			return nil, pkgErrors.Wrap(err, "unmarshal key")
+		}
+
+		return key.PublicKey()
+	}); err != nil {
+		return nil, nil, pkgErrors.Wrap(err, "parse proof")
+	}
+
+	if jti, _ := claims["jti"].(string); jti == "" {
+		return nil, nil, pkgErrors.New("proof does not have ID")
+	}
+
+	if htm, _ := claims["htm"].(string); htm != r.Method {
+		return nil, nil, pkgErrors.Errorf("proof is issued for method %q", htm)
+	}
+
+	// URI is compared without query and fragment, RFC 9449 section 4.3
+	if htu, _ := claims["htu"].(string); strings.SplitN(strings.SplitN(htu, "#", 2)[0], "?", 2)[0] != v.issuer+r.URL.Path {
+		return nil, nil, pkgErrors.Errorf("proof is issued for URI %q", htu)
+	}
+
+	iat, ok := claims["iat"].(float64)
+	if !ok {
+		return nil, nil, pkgErrors.New("proof does not have issue time")
+	}
+
+	// proofs are accepted only within their lifetime around current time, so they can be evicted from replay cache once expired
+	if issuedAt := time.Unix(int64(iat), 0); time.Since(issuedAt) > v.lifetime || time.Until(issuedAt) > v.lifetime {
+		return nil, nil, pkgErrors.New("proof is expired")
+	}
+
+	return claims, &key, nil
+}
+
+// Thumbprint returns thumbprint of the key token is bound to or empty string if token is not bound.
+func Thumbprint(extension map[string]interface{}) string {
+	confirmation, _ := extension[ClaimConfirmation].(map[string]interface{})
+	thumbprint, _ := confirmation[ClaimThumbprint].(string)
+
+	return thumbprint
+}
+
+// Confirmation builds token claim, which binds token to the key.
+func Confirmation(thumbprint string) map[string]interface{} {
+	return map[string]interface{}{ClaimThumbprint: thumbprint}
+}
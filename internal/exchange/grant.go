@@ -0,0 +1,187 @@
+package exchange
+
+import (
+	"context"
+	"github.com/damejeras/auth/internal/client"
+	"github.com/damejeras/auth/internal/dpop"
+	"github.com/damejeras/auth/internal/resource"
+	"github.com/damejeras/auth/pkg/dynamo"
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/manage"
+	pkgErrors "github.com/pkg/errors"
+	"strings"
+	"time"
+)
+
+// GrantType is token exchange grant type defined by RFC 8693.
+const GrantType oauth2.GrantType = "urn:ietf:params:oauth:grant-type:token-exchange"
+
+// TokenTypeAccessToken is the only token type which can be exchanged and issued.
+const TokenTypeAccessToken = "urn:ietf:params:oauth:token-type:access_token"
+
+const (
+	ClaimAudience = "aud"
+	ClaimActor    = "act"
+)
+
+// exchanged tokens are short-lived and can not be refreshed, client has to exchange subject token again.
+var tokenConfig = &manage.Config{AccessTokenExp: time.Hour}
+
+// Grant exchanges user's access token held by client for down-scoped access token of another audience.
+// Issued token carries actor claim, so resource servers know which party acts on user's behalf.
+type Grant struct {
+	tokenStore    dynamo.TokenStore
+	clientStorage *client.Storage
+}
+
+func NewGrant(tokenStore dynamo.TokenStore, clientStorage *client.Storage) *Grant {
+	return &Grant{
+		tokenStore:    tokenStore,
+		clientStorage: clientStorage,
+	}
+}
+
+func (g *Grant) TokenConfig() *manage.Config {
+	return tokenConfig
+}
+
+func (g *Grant) ResolveTokenRequest(tgr *oauth2.TokenGenerateRequest, token *dynamo.Token) error {
+	r := tgr.Request
+	ctx := r.Context()
+
+	if requested := r.FormValue("requested_token_type"); requested != "" && requested != TokenTypeAccessToken {
+		return errors.ErrInvalidRequest
+	}
+
+	subjectToken, err := g.loadToken(ctx, r.FormValue("subject_token"), r.FormValue("subject_token_type"))
+	if err != nil {
+		return err
+	}
+
+	cl, err := g.clientStorage.FindByID(ctx, tgr.ClientID)
+	if err != nil {
+		return pkgErrors.Wrapf(err, "find client %q", tgr.ClientID)
+	}
+
+	// client can exchange only tokens, which were issued to it or are meant for it
+	if !issuedTo(subjectToken, cl) {
+		return errors.ErrInvalidGrant
+	}
+
+	// token bound to a key can be exchanged only with proof signed by the same key,
+	// token handler attaches key of request proof to the context
+	if bound := dpop.Thumbprint(subjectToken.Extension); bound != "" && bound != dpop.Thumbprint(dynamo.ContextExtension(ctx)) {
+		return dpop.ErrInvalidDPoPProof
+	}
+
+	audiences := r.Form["audience"]
+	if len(audiences) == 0 {
+		return errors.ErrInvalidRequest
+	}
+
+	for i := range audiences {
+		if !cl.CanExchangeInto(audiences[i]) {
+			return resource.ErrInvalidTarget
+		}
+	}
+
+	scope := subjectToken.GetScope()
+	if requested := r.FormValue("scope"); requested != "" {
+		if !isSubset(strings.Fields(requested), strings.Fields(scope)) {
+			return errors.ErrInvalidScope
+		}
+
+		scope = requested
+	}
+
+	actor := map[string]interface{}{"sub": tgr.ClientID}
+
+	if actorTokenValue := r.FormValue("actor_token"); actorTokenValue != "" {
+		actorToken, err := g.loadToken(ctx, actorTokenValue, r.FormValue("actor_token_type"))
+		if err != nil {
+			return err
+		}
+
+		actor["sub"] = actorToken.GetClientID()
+		if userID := actorToken.GetUserID(); userID != "" {
+			actor["sub"] = userID
+		}
+	}
+
+	// preserve delegation chain when subject token was obtained by exchange itself
+	if prior := subjectToken.GetExtension(ClaimActor); prior != nil {
+		actor[ClaimActor] = prior
+	}
+
+	token.SetExtension(ClaimAudience, audiences)
+	token.SetExtension(ClaimActor, actor)
+
+	tgr.UserID = subjectToken.GetUserID()
+	tgr.Scope = scope
+
+	// exchanged token must not outlive subject token
+	remaining := time.Until(subjectToken.GetAccessCreateAt().Add(subjectToken.GetAccessExpiresIn()))
+	if remaining < tokenConfig.AccessTokenExp {
+		tgr.AccessTokenExp = remaining
+	}
+
+	return nil
+}
+
+func (g *Grant) ExtendTokenResponse(data map[string]interface{}) {
+	data["issued_token_type"] = TokenTypeAccessToken
+}
+
+// loadToken returns active access token or invalid request error.
+func (g *Grant) loadToken(ctx context.Context, value, tokenType string) (*dynamo.Token, error) {
+	if value == "" || tokenType != TokenTypeAccessToken {
+		return nil, errors.ErrInvalidRequest
+	}
+
+	ti, err := g.tokenStore.GetByAccess(ctx, value)
+	if err != nil {
+		return nil, pkgErrors.Wrap(err, "find token by access")
+	}
+
+	token, ok := ti.(*dynamo.Token)
+	if !ok || token == nil {
+		return nil, errors.ErrInvalidRequest
+	}
+
+	if token.GetAccessCreateAt().Add(token.GetAccessExpiresIn()).Before(time.Now()) {
+		return nil, errors.ErrInvalidRequest
+	}
+
+	return token, nil
+}
+
+// issuedTo reports whether token was issued to the client or its audience includes the client.
+func issuedTo(token *dynamo.Token, cl *client.Client) bool {
+	if token.GetClientID() == cl.ID {
+		return true
+	}
+
+	for _, audience := range resource.Audience(token.Extension) {
+		if audience == cl.ID || (cl.Domain != "" && audience == cl.Domain) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isSubset(subset, set []string) bool {
+	values := make(map[string]struct{}, len(set))
+	for i := range set {
+		values[set[i]] = struct{}{}
+	}
+
+	for i := range subset {
+		if _, ok := values[subset[i]]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
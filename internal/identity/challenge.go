@@ -2,16 +2,20 @@ package identity
 
 import (
 	"context"
+	"github.com/damejeras/auth/internal/consent"
 	"github.com/damejeras/auth/internal/integrity"
 	"time"
 )
 
 type Challenge struct {
-	ID        string
-	ClientID  string
-	Verifier  string
-	Identity  *Identity
-	Footprint *integrity.Footprint
+	ID            string
+	ClientID      string
+	Verifier      string
+	Identity      *Identity
+	Parameters    *Parameters
+	Rejection     *Rejection
+	Footprint     *integrity.Footprint
+	SessionExists bool
 
 	CreatedAt time.Time
 	UpdatedAt time.Time
@@ -21,9 +25,25 @@ type Identity struct {
 	SubjectID string
 }
 
+// Parameters hold authorization request parameters which identity provider may use to render login page.
+type Parameters struct {
+	RequestedScopes               consent.Scopes
+	RequestedAuthorizationDetails consent.AuthorizationDetails
+	LoginHint                     string
+	UILocales                     []string
+	Prompt                        string
+}
+
+// Rejection is the error identity provider responded with instead of authenticating the subject.
+type Rejection struct {
+	Error       string
+	Description string
+}
+
 type ChallengeRepository interface {
 	Store(context.Context, *Challenge) error
 	UpdateWithAuthorization(context.Context, *Challenge) error
+	UpdateWithRejection(context.Context, *Challenge) error
 	Delete(context.Context, *Challenge) error
 	FindByID(context.Context, string) (*Challenge, error)
 	FindByVerifier(context.Context, string) (*Challenge, error)
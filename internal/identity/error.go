@@ -0,0 +1,52 @@
+package identity
+
+import (
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/server"
+	"net/http"
+)
+
+// authorizationError is returned from UserAuthorizationHandler to redirect client with error code,
+// which oauth2 library does not know about, or with custom error description.
+type authorizationError struct {
+	code        string
+	description string
+	// rejected marks errors of user or provider rejecting the request, as opposed to errors of invalid requests.
+	rejected bool
+}
+
+func (e authorizationError) Error() string {
+	return e.code
+}
+
+// errNothingApproved is returned when subject has not approved any of requested scopes.
+var errNothingApproved = authorizationError{code: "access_denied", rejected: true}
+
+// Rejected reports whether authorization failed because user or login and consent providers rejected it.
+func Rejected(err error) bool {
+	authErr, ok := err.(authorizationError)
+
+	return ok && authErr.rejected
+}
+
+// errLockedOut redirects client of locked out subject with access denied error.
+var errLockedOut = authorizationError{
+	code:        "access_denied",
+	description: "Too many invalid authorization requests, try again later",
+}
+
+// InternalErrorHandler converts authorization errors to responses understood by oauth2 server.
+func (m *Manager) InternalErrorHandler() server.InternalErrorHandler {
+	return func(err error) *errors.Response {
+		authErr, ok := err.(authorizationError)
+		if !ok {
+			return nil
+		}
+
+		return &errors.Response{
+			Error:       errors.New(authErr.code),
+			Description: authErr.description,
+			StatusCode:  http.StatusForbidden,
+		}
+	}
+}
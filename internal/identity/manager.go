@@ -2,8 +2,12 @@ package identity
 
 import (
 	"github.com/damejeras/auth/internal/app"
+	"github.com/damejeras/auth/internal/client"
 	"github.com/damejeras/auth/internal/consent"
 	"github.com/damejeras/auth/internal/integrity"
+	"github.com/damejeras/auth/internal/rar"
+	"github.com/damejeras/auth/internal/scope"
+	"github.com/damejeras/auth/internal/session"
 	"github.com/go-oauth2/oauth2/v4/errors"
 	"github.com/go-oauth2/oauth2/v4/server"
 	pkgErrors "github.com/pkg/errors"
@@ -11,6 +15,7 @@ import (
 	"github.com/segmentio/ksuid"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -18,6 +23,9 @@ import (
 const (
 	paramLoginVerifier = "login_verifier"
 
+	promptNone  = "none"
+	promptLogin = "login"
+
 	paramLoginChallenge   = "challenge"
 	paramConsentChallenge = "consent_challenge"
 )
@@ -28,6 +36,17 @@ type Manager struct {
 	challengeRepository        ChallengeRepository
 	consentChallengeRepository consent.ChallengeRepository
 	consentRepository          consent.Repository
+	consentHistoryRepository   consent.HistoryRepository
+	scopeRegistry              *scope.Registry
+	clientStorage              *client.Storage
+	sessionRepository          session.Repository
+	sessionCookie              *session.Cookie
+	sessionNotifier            session.Notifier
+	integrityTracker           *integrity.Tracker
+	integrityGuard             *integrity.Guard
+	integrityBinder            *integrity.Binder
+	sessionLifetime            time.Duration
+	consentLifetime            time.Duration
 	logger                     *zerolog.Logger
 }
 
@@ -35,6 +54,15 @@ func NewManager(
 	challengeRepository ChallengeRepository,
 	consentChallengeRepository consent.ChallengeRepository,
 	consentRepository consent.Repository,
+	consentHistoryRepository consent.HistoryRepository,
+	scopeRegistry *scope.Registry,
+	clientStorage *client.Storage,
+	sessionRepository session.Repository,
+	sessionCookie *session.Cookie,
+	sessionNotifier session.Notifier,
+	integrityTracker *integrity.Tracker,
+	integrityGuard *integrity.Guard,
+	integrityBinder *integrity.Binder,
 	logger *zerolog.Logger,
 	cfg *app.Config,
 ) *Manager {
@@ -44,6 +72,17 @@ func NewManager(
 		challengeRepository:        challengeRepository,
 		consentChallengeRepository: consentChallengeRepository,
 		consentRepository:          consentRepository,
+		consentHistoryRepository:   consentHistoryRepository,
+		scopeRegistry:              scopeRegistry,
+		clientStorage:              clientStorage,
+		sessionRepository:          sessionRepository,
+		sessionCookie:              sessionCookie,
+		sessionNotifier:            sessionNotifier,
+		integrityTracker:           integrityTracker,
+		integrityGuard:             integrityGuard,
+		integrityBinder:            integrityBinder,
+		sessionLifetime:            cfg.SessionConfig.Lifetime,
+		consentLifetime:            cfg.ConsentProviderConfig.RememberFor,
 		logger:                     logger,
 	}
 }
@@ -62,15 +101,31 @@ func (m *Manager) UserAuthorizationHandler() server.UserAuthorizationHandler {
 			}
 
 			if challenge == nil {
-				m.logger.Warn().Msgf("login verifier %q not found", loginVerifier)
+				m.integrityTracker.Track(r, integrity.Violation{
+					Type:        integrity.ViolationUnknownVerifier,
+					Description: "login verifier not found",
+				})
 
 				return "", errors.ErrInvalidRequest
 			}
 
-			if err := challenge.Footprint.Validate(r); err != nil {
+			// subject is known once identity provider has authenticated the user, violations are counted towards it
+			var subjectID string
+			if challenge.Identity != nil {
+				subjectID = challenge.Identity.SubjectID
+			}
+
+			if err := m.integrityBinder.Validate(r, challenge.Footprint); err != nil {
 				switch err.(type) {
 				case integrity.ValidationError:
-					// todo: track violation
+					m.integrityTracker.Track(r, integrity.Violation{
+						Type:        integrity.ViolationFootprintMismatch,
+						Description: err.Error(),
+						ChallengeID: challenge.ID,
+						ClientID:    challenge.ClientID,
+						SubjectID:   subjectID,
+					})
+
 					return "", errors.ErrAccessDenied
 				default:
 					m.logger.Error().Err(err).Msg("validate request")
@@ -79,51 +134,60 @@ func (m *Manager) UserAuthorizationHandler() server.UserAuthorizationHandler {
 				}
 			}
 
-			if challenge.Identity == nil {
-				// todo: track violation
-				return "", errors.ErrAccessDenied
+			if challenge.Rejection != nil {
+				if err := m.challengeRepository.Delete(r.Context(), challenge); err != nil {
+					m.logger.Error().Err(err).Msgf("delete login challenge %q", challenge.ID)
+
+					return "", errors.ErrServerError
+				}
+
+				return "", authorizationError{code: challenge.Rejection.Error, description: challenge.Rejection.Description, rejected: true}
 			}
 
-			cs, err := m.consentRepository.FindByClientAndSubject(r.Context(), challenge.ClientID, challenge.Identity.SubjectID)
-			if err != nil {
-				m.logger.Error().Err(err).Msgf("find client's %q consent for subject %q", challenge.ClientID, challenge.Identity.SubjectID)
+			if subjectID == "" {
+				m.integrityTracker.Track(r, integrity.Violation{
+					Type:        integrity.ViolationUnauthenticatedChallenge,
+					Description: "login verifier used before identity provider authenticated the user",
+					ChallengeID: challenge.ID,
+					ClientID:    challenge.ClientID,
+				})
 
-				return "", errors.ErrServerError
+				return "", errors.ErrAccessDenied
 			}
 
-			requestedScopes := scopeParamToScopes(r.URL.Query().Get("scope"))
+			if err := m.checkLockout(r, challenge.Identity.SubjectID); err != nil {
+				if err := m.challengeRepository.Delete(r.Context(), challenge); err != nil {
+					m.logger.Error().Err(err).Msgf("delete login challenge %q", challenge.ID)
+				}
 
-			if cs == nil || !cs.Scopes.HasAll(requestedScopes) {
-				var consentChallenge *consent.Challenge
-				if cs != nil {
-					consentChallenge, err = m.createConsentChallenge(r, requestedScopes, requestedScopes.Diff(cs.Scopes), challenge.ClientID, challenge.Identity.SubjectID)
-					if err != nil {
-						m.logger.Error().Err(err).Msg("create consent challenge")
+				return "", err
+			}
 
-						return "", errors.ErrServerError
-					}
-				} else {
-					consentChallenge, err = m.createConsentChallenge(r, requestedScopes, requestedScopes, challenge.ClientID, challenge.Identity.SubjectID)
-					if err != nil {
-						m.logger.Error().Err(err).Msg("create consent challenge")
+			sess, err := m.startSession(w, r, challenge.Identity.SubjectID)
+			if err != nil {
+				m.logger.Error().Err(err).Msgf("start session for subject %q", challenge.Identity.SubjectID)
 
-						return "", errors.ErrServerError
-					}
-				}
+				return "", errors.ErrServerError
+			}
+
+			subjectID, err = m.authorizeSubject(w, r, challenge.ClientID, sess.SubjectID)
+			if err != nil || subjectID == "" {
+				return "", err
+			}
 
-				w.Header().Add("Location", consentChallenge.Footprint.RedirectURL)
-				w.WriteHeader(http.StatusFound)
+			if err := m.joinSession(r, sess, challenge.ClientID); err != nil {
+				m.logger.Error().Err(err).Msgf("join session %q", sess.ID)
 
-				return "", nil
+				return "", errors.ErrServerError
 			}
 
 			if err := m.challengeRepository.Delete(r.Context(), challenge); err != nil {
-				m.logger.Error().Err(err).Msgf("delete login challenge %q", challenge)
+				m.logger.Error().Err(err).Msgf("delete login challenge %q", challenge.ID)
 
 				return "", errors.ErrServerError
 			}
 
-			return challenge.Identity.SubjectID, nil
+			return subjectID, nil
 		}
 
 		consentVerifier := r.URL.Query().Get("consent_verifier")
@@ -137,15 +201,26 @@ func (m *Manager) UserAuthorizationHandler() server.UserAuthorizationHandler {
 				return "", errors.ErrServerError
 			}
 
-			if consentChallenge == nil || consentChallenge.GrantedScopes == nil {
-				// todo track violation
+			if consentChallenge == nil {
+				m.integrityTracker.Track(r, integrity.Violation{
+					Type:        integrity.ViolationUnknownVerifier,
+					Description: "consent verifier not found",
+				})
+
 				return "", errors.ErrAccessDenied
 			}
 
-			if err := consentChallenge.Footprint.Validate(r); err != nil {
+			if err := m.integrityBinder.Validate(r, consentChallenge.Footprint); err != nil {
 				switch err.(type) {
 				case integrity.ValidationError:
-					// todo: track violation
+					m.integrityTracker.Track(r, integrity.Violation{
+						Type:        integrity.ViolationFootprintMismatch,
+						Description: err.Error(),
+						ChallengeID: consentChallenge.ID,
+						ClientID:    consentChallenge.ClientID,
+						SubjectID:   consentChallenge.SubjectID,
+					})
+
 					return "", errors.ErrAccessDenied
 				default:
 					m.logger.Error().Err(err).Msg("validate footprint")
@@ -154,10 +229,75 @@ func (m *Manager) UserAuthorizationHandler() server.UserAuthorizationHandler {
 				}
 			}
 
+			if err := m.checkLockout(r, consentChallenge.SubjectID); err != nil {
+				if err := m.consentChallengeRepository.Delete(r.Context(), consentChallenge); err != nil {
+					m.logger.Error().Err(err).Msgf("delete consent challenge %q", consentChallenge.ID)
+				}
+
+				return "", err
+			}
+
+			if consentChallenge.Rejection != nil {
+				if err := m.consentChallengeRepository.Delete(r.Context(), consentChallenge); err != nil {
+					m.logger.Error().Err(err).Msgf("delete consent challenge %q", consentChallenge.ID)
+
+					return "", errors.ErrServerError
+				}
+
+				return "", authorizationError{code: consentChallenge.Rejection.Error, description: consentChallenge.Rejection.Description, rejected: true}
+			}
+
+			if consentChallenge.GrantedScopes == nil {
+				m.integrityTracker.Track(r, integrity.Violation{
+					Type:        integrity.ViolationUngrantedChallenge,
+					Description: "consent verifier used before consent provider granted consent",
+					ChallengeID: consentChallenge.ID,
+					ClientID:    consentChallenge.ClientID,
+					SubjectID:   consentChallenge.SubjectID,
+				})
+
+				return "", errors.ErrAccessDenied
+			}
+
+			sess, err := m.currentSession(r)
+			if err != nil {
+				m.logger.Error().Err(err).Msg("find current session")
+
+				return "", errors.ErrServerError
+			}
+
+			if sess != nil && sess.SubjectID == consentChallenge.SubjectID {
+				if err := m.joinSession(r, sess, consentChallenge.ClientID); err != nil {
+					m.logger.Error().Err(err).Msgf("join session %q", sess.ID)
+
+					return "", errors.ErrServerError
+				}
+			}
+
+			// scopes consent provider was asked for, but has not granted, are not passed to the token
+			deniedScopes := consentChallenge.MissingScopes.Diff(consentChallenge.GrantedScopes)
+			approvedScopes := consentChallenge.RequestedScopes.Diff(deniedScopes)
+
+			if len(consentChallenge.RequestedScopes) > 0 && len(approvedScopes) == 0 {
+				if err := m.consentChallengeRepository.Delete(r.Context(), consentChallenge); err != nil {
+					m.logger.Error().Err(err).Msgf("delete consent challenge %q", consentChallenge.ID)
+
+					return "", errors.ErrServerError
+				}
+
+				return "", errNothingApproved
+			}
+
+			if err := setApprovedScope(r, approvedScopes); err != nil {
+				m.logger.Error().Err(err).Msg("set approved scope")
+
+				return "", errors.ErrServerError
+			}
+
 			consentChallenge.Used = true
 
 			if err := m.consentChallengeRepository.Delete(r.Context(), consentChallenge); err != nil {
-				m.logger.Error().Err(err).Msgf("delete consent challenge %q", consentChallenge)
+				m.logger.Error().Err(err).Msgf("delete consent challenge %q", consentChallenge.ID)
 
 				return "", errors.ErrServerError
 			}
@@ -165,9 +305,43 @@ func (m *Manager) UserAuthorizationHandler() server.UserAuthorizationHandler {
 			return consentChallenge.SubjectID, nil
 		}
 
+		sess, err := m.currentSession(r)
+		if err != nil {
+			m.logger.Error().Err(err).Msg("find current session")
+
+			return "", errors.ErrServerError
+		}
+
+		if sess != nil && !hasPrompt(r, promptLogin) && sessionSatisfiesMaxAge(r, sess) {
+			m.logger.Trace().Msgf("serve subject %q from session %q", sess.SubjectID, sess.ID)
+
+			if err := m.checkLockout(r, sess.SubjectID); err != nil {
+				return "", err
+			}
+
+			clientID := r.URL.Query().Get("client_id")
+
+			subjectID, err := m.authorizeSubject(w, r, clientID, sess.SubjectID)
+			if err != nil || subjectID == "" {
+				return "", err
+			}
+
+			if err := m.joinSession(r, sess, clientID); err != nil {
+				m.logger.Error().Err(err).Msgf("join session %q", sess.ID)
+
+				return "", errors.ErrServerError
+			}
+
+			return subjectID, nil
+		}
+
+		if hasPrompt(r, promptNone) {
+			return "", authorizationError{code: "login_required"}
+		}
+
 		m.logger.Trace().Msg("creating new login challenge")
 
-		challenge, err := m.createLoginChallenge(r)
+		challenge, err := m.createLoginChallenge(r, sess)
 		if err != nil {
 			m.logger.Error().Err(err).Msg("create login challenge")
 
@@ -181,7 +355,256 @@ func (m *Manager) UserAuthorizationHandler() server.UserAuthorizationHandler {
 	}
 }
 
-func (m *Manager) createConsentChallenge(r *http.Request, requested, missing consent.Scopes, clientID, subjectID string) (*consent.Challenge, error) {
+// checkLockout rejects authorization of subject locked out after too many integrity violations. Challenges redeemed
+// during lockout are deleted by the caller, so all challenges opened before the lockout are invalidated.
+func (m *Manager) checkLockout(r *http.Request, subjectID string) error {
+	lockout, err := m.integrityGuard.Locked(r.Context(), integrity.LockoutSubject, subjectID)
+	if err != nil {
+		m.logger.Error().Err(err).Msgf("check subject %q lockout", subjectID)
+
+		return errors.ErrServerError
+	}
+
+	if lockout != nil {
+		return errLockedOut
+	}
+
+	return nil
+}
+
+// authorizeSubject returns subject ID if client has subject's consent for requested scopes,
+// otherwise redirects user agent to consent provider. Scopes and details, which do not need explicit consent,
+// are recorded in subject's consent without asking.
+func (m *Manager) authorizeSubject(w http.ResponseWriter, r *http.Request, clientID, subjectID string) (string, error) {
+	cs, err := m.consentRepository.FindByClientAndSubject(r.Context(), clientID, subjectID)
+	if err != nil {
+		m.logger.Error().Err(err).Msgf("find client's %q consent for subject %q", clientID, subjectID)
+
+		return "", errors.ErrServerError
+	}
+
+	cl, err := m.clientStorage.FindByID(r.Context(), clientID)
+	if err != nil {
+		m.logger.Error().Err(err).Msgf("find client %q", clientID)
+
+		return "", errors.ErrServerError
+	}
+
+	requestedScopes := scopeParamToScopes(r.URL.Query().Get("scope"))
+	requestedDetails := requestedAuthorizationDetails(r)
+
+	// scopes, which do not require explicit consent, are granted implicitly
+	registryScopes, err := m.scopeRegistry.RequiringConsent(r.Context(), requestedScopes.ToSlice())
+	if err != nil {
+		m.logger.Error().Err(err).Msg("find scopes requiring consent")
+
+		return "", errors.ErrServerError
+	}
+
+	explicitScopes := consent.BuildScopes(registryScopes)
+	explicitDetails := requestedDetails
+
+	switch {
+	case cl != nil && cl.SkipConsent:
+		explicitScopes = make(consent.Scopes)
+		explicitDetails = make(consent.AuthorizationDetails, 0)
+	case cl != nil:
+		explicitScopes = explicitScopes.Diff(consent.BuildScopes(cl.ImplicitConsentScopes))
+	}
+
+	implicitScopes := requestedScopes.Diff(explicitScopes)
+	implicitDetails := requestedDetails.Diff(explicitDetails)
+
+	// expired consent is asked for again as a whole
+	missingScopes := explicitScopes
+	missingDetails := explicitDetails
+	if cs != nil && !cs.Expired() {
+		// consent to broad scope satisfies narrower scopes it implies
+		unsatisfiedScopes, err := m.scopeRegistry.Unsatisfied(r.Context(), cs.Scopes.ToSlice(), explicitScopes.ToSlice())
+		if err != nil {
+			m.logger.Error().Err(err).Msg("find unsatisfied scopes")
+
+			return "", errors.ErrServerError
+		}
+
+		missingScopes = consent.BuildScopes(unsatisfiedScopes)
+		missingDetails = explicitDetails.Diff(cs.AuthorizationDetails)
+	}
+
+	if len(missingScopes) == 0 && len(missingDetails) == 0 {
+		if err := m.grantImplicitly(r, cs, clientID, subjectID, implicitScopes, implicitDetails); err != nil {
+			m.logger.Error().Err(err).Msgf("grant client %q implicit consent of subject %q", clientID, subjectID)
+
+			return "", errors.ErrServerError
+		}
+
+		return subjectID, nil
+	}
+
+	if hasPrompt(r, promptNone) {
+		return "", authorizationError{code: "consent_required"}
+	}
+
+	consentChallenge, err := m.createConsentChallenge(r, requestedScopes, missingScopes, requestedDetails, missingDetails, clientID, subjectID)
+	if err != nil {
+		m.logger.Error().Err(err).Msg("create consent challenge")
+
+		return "", errors.ErrServerError
+	}
+
+	w.Header().Add("Location", consentChallenge.Footprint.RedirectURL)
+	w.WriteHeader(http.StatusFound)
+
+	return "", nil
+}
+
+// grantImplicitly records implicitly granted scopes and details in subject's consent and its history, so the grant
+// can be audited and token endpoint finds approved authorization details the same way it does for explicit consent.
+func (m *Manager) grantImplicitly(
+	r *http.Request,
+	cs *consent.Consent,
+	clientID, subjectID string,
+	scopes consent.Scopes,
+	details consent.AuthorizationDetails,
+) error {
+	var previousScopes consent.Scopes
+	var previousDetails consent.AuthorizationDetails
+
+	switch {
+	case len(scopes) == 0 && len(details) == 0:
+		return nil
+	case cs == nil:
+		cs = &consent.Consent{
+			ID:                   ksuid.New().String(),
+			ClientID:             clientID,
+			SubjectID:            subjectID,
+			Scopes:               scopes,
+			AuthorizationDetails: details,
+			ExpiresAt:            time.Now().Add(m.consentLifetime),
+		}
+
+		if err := m.consentRepository.Store(r.Context(), cs); err != nil {
+			return pkgErrors.Wrap(err, "store consent")
+		}
+	case cs.Scopes.HasAll(scopes) && cs.AuthorizationDetails.HasAll(details) && !cs.Expired():
+		return nil
+	default:
+		previousScopes, previousDetails = cs.Scopes, cs.AuthorizationDetails
+
+		if cs.Expired() {
+			cs.Scopes = scopes
+			cs.AuthorizationDetails = details
+			cs.ExpiresAt = time.Now().Add(m.consentLifetime)
+		} else {
+			cs.Scopes = cs.Scopes.Merge(scopes)
+			cs.AuthorizationDetails = cs.AuthorizationDetails.Merge(details)
+		}
+
+		if err := m.consentRepository.UpdateWithGrant(r.Context(), cs); err != nil {
+			return pkgErrors.Wrap(err, "update consent with grant")
+		}
+	}
+
+	event := consent.NewEvent(consent.EventGrantedImplicitly, cs, previousScopes, previousDetails)
+	event.RequestID = app.GetCurrentRequestID(r)
+
+	return pkgErrors.Wrap(m.consentHistoryRepository.Store(r.Context(), event), "store consent event")
+}
+
+// AuthorizeScopeHandler returns scope subject has approved, so authorization code is not issued for scopes
+// consent provider has not granted.
+func (m *Manager) AuthorizeScopeHandler() server.AuthorizeScopeHandler {
+	return func(w http.ResponseWriter, r *http.Request) (string, error) {
+		return r.FormValue("scope"), nil
+	}
+}
+
+// setApprovedScope replaces scope of authorization request with approved one. Oauth2 server reads request
+// parameters from the form, which is shared with authorize scope handler.
+func setApprovedScope(r *http.Request, scopes consent.Scopes) error {
+	if err := r.ParseForm(); err != nil {
+		return err
+	}
+
+	r.Form.Set("scope", strings.Join(scopes.ToSlice(), " "))
+
+	return nil
+}
+
+func (m *Manager) currentSession(r *http.Request) (*session.Session, error) {
+	return session.FromRequest(r, m.sessionCookie, m.sessionRepository)
+}
+
+// startSession marks current session as re-authenticated if it belongs to the same subject, otherwise replaces it with a new one.
+func (m *Manager) startSession(w http.ResponseWriter, r *http.Request, subjectID string) (*session.Session, error) {
+	sess, err := m.currentSession(r)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	if sess != nil && sess.SubjectID == subjectID {
+		sess.AuthenticatedAt = now
+		sess.ExpiresAt = now.Add(m.sessionLifetime)
+
+		if err := m.sessionRepository.UpdateWithAuthentication(r.Context(), sess); err != nil {
+			return nil, pkgErrors.Wrap(err, "update session")
+		}
+
+		m.sessionCookie.Write(w, sess)
+
+		return sess, nil
+	}
+
+	if sess != nil {
+		if err := m.sessionRepository.Delete(r.Context(), sess); err != nil {
+			return nil, pkgErrors.Wrap(err, "delete session")
+		}
+
+		if err := m.sessionNotifier.NotifyLogout(r.Context(), sess); err != nil {
+			return nil, pkgErrors.Wrap(err, "notify clients about session logout")
+		}
+	}
+
+	sess = &session.Session{
+		ID:              ksuid.New().String(),
+		SubjectID:       subjectID,
+		UserAgent:       r.UserAgent(),
+		IPAddress:       app.RemoteIP(r),
+		AuthenticatedAt: now,
+		ExpiresAt:       now.Add(m.sessionLifetime),
+		CreatedAt:       now,
+	}
+
+	if err := m.sessionRepository.Store(r.Context(), sess); err != nil {
+		return nil, pkgErrors.Wrap(err, "store session")
+	}
+
+	m.sessionCookie.Write(w, sess)
+
+	return sess, nil
+}
+
+// joinSession records that client was authorized during the session, and binds authorization code to it.
+func (m *Manager) joinSession(r *http.Request, sess *session.Session, clientID string) error {
+	session.Bind(r, sess)
+
+	if sess.HasClient(clientID) {
+		return nil
+	}
+
+	sess.ClientIDs = append(sess.ClientIDs, clientID)
+
+	return pkgErrors.Wrap(m.sessionRepository.UpdateWithClientIDs(r.Context(), sess), "update session clients")
+}
+
+func (m *Manager) createConsentChallenge(
+	r *http.Request,
+	requested, missing consent.Scopes,
+	requestedDetails, missingDetails consent.AuthorizationDetails,
+	clientID, subjectID string,
+) (*consent.Challenge, error) {
 	challengeID := ksuid.New().String()
 
 	cpURL, err := url.Parse(m.consentProviderURL)
@@ -194,21 +617,23 @@ func (m *Manager) createConsentChallenge(r *http.Request, requested, missing con
 
 	cpURL.RawQuery = queryValues.Encode()
 
+	footprint, err := m.integrityBinder.Bind(r, cpURL.String())
+	if err != nil {
+		return nil, pkgErrors.Wrap(err, "bind footprint")
+	}
+
 	challenge := consent.Challenge{
-		ID:              challengeID,
-		Verifier:        ksuid.New().String(),
-		ClientID:        clientID,
-		SubjectID:       subjectID,
-		RequestedScopes: requested,
-		MissingScopes:   missing,
-		GrantedScopes:   nil,
-		Footprint: &integrity.Footprint{
-			RequestID:   app.GetCurrentRequestID(r),
-			RedirectURL: cpURL.String(),
-			// TODO: use r.URL.Scheme ?
-			RequestURL: "http" + "://" + r.Host + r.URL.RequestURI(),
-		},
-		CreatedAt: time.Now(),
+		ID:                            challengeID,
+		Verifier:                      ksuid.New().String(),
+		ClientID:                      clientID,
+		SubjectID:                     subjectID,
+		RequestedScopes:               requested,
+		MissingScopes:                 missing,
+		GrantedScopes:                 nil,
+		RequestedAuthorizationDetails: requestedDetails,
+		MissingAuthorizationDetails:   missingDetails,
+		Footprint:                     footprint,
+		CreatedAt:                     time.Now(),
 	}
 
 	if err := m.consentChallengeRepository.Store(r.Context(), &challenge); err != nil {
@@ -218,7 +643,7 @@ func (m *Manager) createConsentChallenge(r *http.Request, requested, missing con
 	return &challenge, nil
 }
 
-func (m *Manager) createLoginChallenge(r *http.Request) (*Challenge, error) {
+func (m *Manager) createLoginChallenge(r *http.Request, sess *session.Session) (*Challenge, error) {
 	challengeID := ksuid.New().String()
 
 	idpURL, err := url.Parse(m.identityProviderURL)
@@ -231,18 +656,28 @@ func (m *Manager) createLoginChallenge(r *http.Request) (*Challenge, error) {
 
 	idpURL.RawQuery = queryValues.Encode()
 
+	footprint, err := m.integrityBinder.Bind(r, idpURL.String())
+	if err != nil {
+		return nil, pkgErrors.Wrap(err, "bind footprint")
+	}
+
+	requestValues := r.URL.Query()
+
 	challenge := Challenge{
 		ID:       challengeID,
-		ClientID: r.URL.Query().Get("client_id"),
+		ClientID: requestValues.Get("client_id"),
 		Verifier: ksuid.New().String(),
-		Footprint: &integrity.Footprint{
-			RequestID:   app.GetCurrentRequestID(r),
-			RedirectURL: idpURL.String(),
-			// TODO: use r.URL.Scheme ???
-			RequestURL: "http" + "://" + r.Host + r.URL.RequestURI(),
+		Parameters: &Parameters{
+			RequestedScopes:               scopeParamToScopes(requestValues.Get("scope")),
+			RequestedAuthorizationDetails: requestedAuthorizationDetails(r),
+			LoginHint:                     requestValues.Get("login_hint"),
+			UILocales:                     strings.Fields(requestValues.Get("ui_locales")),
+			Prompt:                        requestValues.Get("prompt"),
 		},
-		CreatedAt: time.Time{},
-		UpdatedAt: time.Time{},
+		SessionExists: sess != nil,
+		Footprint:     footprint,
+		CreatedAt:     time.Time{},
+		UpdatedAt:     time.Time{},
 	}
 
 	if err := m.challengeRepository.Store(r.Context(), &challenge); err != nil {
@@ -278,12 +713,39 @@ func stringSliceDifference(i1, i2 []string) []string {
 	return result
 }
 
-func scopeParamToScopes(input string) consent.Scopes {
-	result := make(map[string]struct{})
-	split := strings.Split(input, " ")
-	for i := range split {
-		result[split[i]] = struct{}{}
+func hasPrompt(r *http.Request, prompt string) bool {
+	for _, value := range strings.Fields(r.URL.Query().Get("prompt")) {
+		if value == prompt {
+			return true
+		}
 	}
 
-	return result
+	return false
+}
+
+// sessionSatisfiesMaxAge checks if session authentication is recent enough for max_age request parameter.
+func sessionSatisfiesMaxAge(r *http.Request, sess *session.Session) bool {
+	maxAge := r.URL.Query().Get("max_age")
+	if maxAge == "" {
+		return true
+	}
+
+	seconds, err := strconv.Atoi(maxAge)
+	if err != nil || seconds < 0 {
+		return false
+	}
+
+	return sess.AuthenticatedWithin(time.Duration(seconds) * time.Second)
+}
+
+// requestedAuthorizationDetails parses authorization_details request parameter.
+// Parameter is validated by authorization details middleware, so parse errors are not expected here.
+func requestedAuthorizationDetails(r *http.Request) consent.AuthorizationDetails {
+	details, _ := consent.ParseAuthorizationDetails(r.URL.Query().Get(rar.ParamAuthorizationDetails))
+
+	return details
+}
+
+func scopeParamToScopes(input string) consent.Scopes {
+	return consent.BuildScopes(strings.Fields(input))
 }
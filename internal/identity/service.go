@@ -3,17 +3,28 @@ package identity
 import (
 	"context"
 	"github.com/damejeras/auth/api"
+	"github.com/damejeras/auth/internal/client"
 	"github.com/pkg/errors"
 	"net/url"
 )
 
+// loginErrors are error codes identity provider may reject login challenge with.
+var loginErrors = map[string]struct{}{
+	"access_denied":              {},
+	"login_required":             {},
+	"interaction_required":       {},
+	"account_selection_required": {},
+}
+
 type service struct {
 	challengeRepository ChallengeRepository
+	clientStorage       *client.Storage
 }
 
-func NewService(challengeRepository ChallengeRepository) api.IdentityService {
+func NewService(challengeRepository ChallengeRepository, clientStorage *client.Storage) api.IdentityService {
 	return &service{
 		challengeRepository: challengeRepository,
+		clientStorage:       clientStorage,
 	}
 }
 
@@ -23,7 +34,7 @@ func (s *service) Authenticate(ctx context.Context, request api.AuthenticateRequ
 		return nil, errors.Wrap(err, "find challenge")
 	}
 
-	if challenge == nil || challenge.Identity.SubjectID != "" {
+	if challenge == nil || challenge.Identity.SubjectID != "" || challenge.Rejection != nil {
 		return nil, errors.New("invalid challenge")
 	}
 
@@ -33,21 +44,98 @@ func (s *service) Authenticate(ctx context.Context, request api.AuthenticateRequ
 		return nil, errors.Wrap(err, "update challenge")
 	}
 
+	redirectURL, err := verifierRedirectURL(challenge)
+	if err != nil {
+		return nil, errors.Wrap(err, "build redirect url")
+	}
+
+	return &api.AuthenticateResponse{
+		RedirectURL: redirectURL,
+	}, nil
+}
+
+func (s *service) RejectLogin(ctx context.Context, request api.RejectLoginRequest) (*api.RejectLoginResponse, error) {
+	if request.Error == "" {
+		request.Error = "access_denied"
+	}
+
+	if _, ok := loginErrors[request.Error]; !ok {
+		return nil, errors.Errorf("unsupported error %q", request.Error)
+	}
+
+	challenge, err := s.challengeRepository.FindByID(ctx, request.ChallengeID)
+	if err != nil {
+		return nil, errors.Wrap(err, "find challenge")
+	}
+
+	if challenge == nil || challenge.Identity.SubjectID != "" || challenge.Rejection != nil {
+		return nil, errors.New("invalid challenge")
+	}
+
+	challenge.Rejection = &Rejection{
+		Error:       request.Error,
+		Description: request.ErrorDescription,
+	}
+
+	if err := s.challengeRepository.UpdateWithRejection(ctx, challenge); err != nil {
+		return nil, errors.Wrap(err, "update challenge")
+	}
+
+	redirectURL, err := verifierRedirectURL(challenge)
+	if err != nil {
+		return nil, errors.Wrap(err, "build redirect url")
+	}
+
+	return &api.RejectLoginResponse{
+		RedirectURL: redirectURL,
+	}, nil
+}
+
+func (s *service) ShowLoginChallenge(ctx context.Context, request api.ShowLoginChallengeRequest) (*api.ShowLoginChallengeResponse, error) {
+	challenge, err := s.challengeRepository.FindByID(ctx, request.LoginChallenge)
+	if err != nil {
+		return nil, errors.Wrap(err, "find challenge")
+	}
+
+	if challenge == nil || challenge.Identity.SubjectID != "" || challenge.Rejection != nil {
+		return nil, errors.New("invalid challenge")
+	}
+
+	cl, err := s.clientStorage.FindByID(ctx, challenge.ClientID)
+	if err != nil {
+		return nil, errors.Wrap(err, "find client")
+	}
+
+	if cl == nil {
+		return nil, errors.New("client not found")
+	}
+
+	return &api.ShowLoginChallengeResponse{
+		ClientID:                      cl.ID,
+		ClientDomain:                  cl.Domain,
+		RequestedScopes:               challenge.Parameters.RequestedScopes.ToSlice(),
+		RequestedAuthorizationDetails: challenge.Parameters.RequestedAuthorizationDetails.ToSlice(),
+		LoginHint:                     challenge.Parameters.LoginHint,
+		UILocales:                     challenge.Parameters.UILocales,
+		Prompt:                        challenge.Parameters.Prompt,
+		SessionExists:                 challenge.SessionExists,
+	}, nil
+}
+
+func verifierRedirectURL(challenge *Challenge) (string, error) {
 	requestURL, err := url.Parse(challenge.Footprint.RequestURL)
 	if err != nil {
-		return nil, errors.Wrap(err, "parse request url")
+		return "", errors.Wrap(err, "parse request url")
 	}
 
 	urlValues, err := url.ParseQuery(requestURL.RawQuery)
 	if err != nil {
-		return nil, errors.Wrap(err, "parse url values")
+		return "", errors.Wrap(err, "parse url values")
 	}
 
 	urlValues.Add(paramLoginVerifier, challenge.Verifier)
 
 	requestURL.RawQuery = urlValues.Encode()
 
-	return &api.AuthenticateResponse{
-		RedirectURL: requestURL.String(),
-	}, nil
+	return requestURL.String(), nil
 }
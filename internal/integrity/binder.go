@@ -0,0 +1,220 @@
+package integrity
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/damejeras/auth/internal/app"
+	"github.com/pkg/errors"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Footprint modes.
+const (
+	// ModeStored validates footprint stored with challenge, including Referer of returning request.
+	ModeStored = "stored"
+	// ModeSigned carries encrypted footprint in redirect URL, so it does not depend on Referer header,
+	// which is not sent by user agents with no-referrer policy. It is not stateless, footprint stored with challenge
+	// still holds request ID sealed footprint is checked against, and request URL user agent is redirected back to.
+	ModeSigned = "signed"
+)
+
+// paramFootprint carries sealed footprint through login and consent provider redirects.
+const paramFootprint = "footprint"
+
+// ReturnParams are parameters login and consent providers append to authorization request URL,
+// they are the only ones, which are not part of the original request.
+func ReturnParams() []string {
+	return append([]string{paramFootprint}, verifierParams...)
+}
+
+// seal is encrypted content of signed footprint.
+type seal struct {
+	RequestID  string
+	Parameters map[string]string
+	ExpiresAt  int64
+}
+
+// Binder binds requests returning from login and consent providers to authorization requests they were
+// redirected from.
+type Binder struct {
+	mode     string
+	aead     cipher.AEAD
+	lifetime time.Duration
+}
+
+func NewBinder(cfg *app.Config) (*Binder, error) {
+	binder := Binder{
+		mode:     cfg.IntegrityConfig.FootprintMode,
+		lifetime: cfg.IntegrityConfig.FootprintLifetime,
+	}
+
+	switch binder.mode {
+	case ModeStored:
+		return &binder, nil
+	case ModeSigned:
+	default:
+		return nil, errors.Errorf("unknown footprint mode %q", binder.mode)
+	}
+
+	if cfg.IntegrityConfig.FootprintSecret == "" {
+		return nil, errors.New("footprint secret is required in signed mode")
+	}
+
+	key := sha256.Sum256([]byte(cfg.IntegrityConfig.FootprintSecret))
+
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, errors.Wrap(err, "create cipher")
+	}
+
+	binder.aead, err = cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "create gcm")
+	}
+
+	return &binder, nil
+}
+
+// Bind creates footprint of authorization request, which user agent is redirected to redirectURL from.
+// In signed mode request URL user agent returns to carries sealed footprint bound to the request ID cookie.
+func (b *Binder) Bind(r *http.Request, redirectURL string) (*Footprint, error) {
+	footprint := Footprint{
+		RequestID:   app.GetCurrentRequestID(r),
+		RedirectURL: redirectURL,
+		RequestURL:  RequestURL(r),
+	}
+
+	requestURL, err := url.Parse(footprint.RequestURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "parse request url")
+	}
+
+	// parameters of previous return are replaced by the ones of this redirect, otherwise user agent
+	// returning from consent provider would be taken for one returning from login provider again
+	values := requestURL.Query()
+	values.Del(paramFootprint)
+	for i := range verifierParams {
+		values.Del(verifierParams[i])
+	}
+
+	if b.mode != ModeSigned {
+		requestURL.RawQuery = values.Encode()
+		footprint.RequestURL = requestURL.String()
+
+		return &footprint, nil
+	}
+
+	content := seal{
+		RequestID:  footprint.RequestID,
+		Parameters: make(map[string]string, len(validationParams)),
+		ExpiresAt:  time.Now().Add(b.lifetime).Unix(),
+	}
+
+	// absent parameters are read back as empty, so they are left out to keep URL short
+	for i := range validationParams {
+		if value := values.Get(validationParams[i]); value != "" {
+			content.Parameters[validationParams[i]] = value
+		}
+	}
+
+	sealed, err := b.seal(&content)
+	if err != nil {
+		return nil, errors.Wrap(err, "seal footprint")
+	}
+
+	values.Set(paramFootprint, sealed)
+	requestURL.RawQuery = values.Encode()
+
+	footprint.RequestURL = requestURL.String()
+	footprint.Sealed = true
+
+	return &footprint, nil
+}
+
+// Validate checks that request returning from login or consent provider matches the footprint. Sealed footprints
+// are validated from the returning request and request ID of stored footprint, so they stay valid after footprint
+// mode is changed. Sealed footprint of another authorization request can not be used to return with verifier
+// of this one, as both its request ID and request ID cookie have to match the challenge.
+func (b *Binder) Validate(r *http.Request, footprint *Footprint) error {
+	if !footprint.Sealed {
+		return footprint.Validate(r)
+	}
+
+	if b.aead == nil {
+		return errors.New("sealed footprint can not be opened in stored mode")
+	}
+
+	content, err := b.open(r.URL.Query().Get(paramFootprint))
+	if err != nil {
+		return ValidationError(fmt.Sprintf("invalid footprint: %s", err))
+	}
+
+	if time.Now().Unix() > content.ExpiresAt {
+		return ValidationError("footprint expired")
+	}
+
+	if content.RequestID != footprint.RequestID || app.GetPreviousRequestID(r) != content.RequestID {
+		return ValidationError("request ID does not match")
+	}
+
+	requestURL, err := url.Parse(RequestURL(r))
+	if err != nil {
+		return errors.Wrap(err, "parse current request url")
+	}
+
+	requestValues := requestURL.Query()
+
+	for i := range validationParams {
+		if content.Parameters[validationParams[i]] != requestValues.Get(validationParams[i]) {
+			return ValidationError(fmt.Sprintf("request parameter %q does not match", validationParams[i]))
+		}
+	}
+
+	return nil
+}
+
+func (b *Binder) seal(content *seal) (string, error) {
+	plaintext, err := json.Marshal(content)
+	if err != nil {
+		return "", errors.Wrap(err, "marshal footprint")
+	}
+
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", errors.Wrap(err, "generate nonce")
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b.aead.Seal(nonce, nonce, plaintext, nil)), nil
+}
+
+func (b *Binder) open(sealed string) (*seal, error) {
+	ciphertext, err := base64.RawURLEncoding.DecodeString(sealed)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode footprint")
+	}
+
+	if len(ciphertext) < b.aead.NonceSize() {
+		return nil, errors.New("footprint is too short")
+	}
+
+	nonce, ciphertext := ciphertext[:b.aead.NonceSize()], ciphertext[b.aead.NonceSize():]
+
+	plaintext, err := b.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypt footprint")
+	}
+
+	var content seal
+	if err := json.Unmarshal(plaintext, &content); err != nil {
+		return nil, errors.Wrap(err, "unmarshal footprint")
+	}
+
+	return &content, nil
+}
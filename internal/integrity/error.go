@@ -0,0 +1,17 @@
+package integrity
+
+import (
+	stdErrors "errors"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"net/http"
+)
+
+// ErrLockedOut is returned to authorization requests of locked out clients and source IP addresses.
+var ErrLockedOut = stdErrors.New("access_denied")
+
+// register error with oauth2 server, so it is rendered as regular oauth2 error.
+func init() {
+	errors.Descriptions[ErrLockedOut] = "Too many invalid authorization requests, try again later"
+
+	errors.StatusCodes[ErrLockedOut] = http.StatusTooManyRequests
+}
@@ -1,6 +1,7 @@
 package integrity
 
 import (
+	"context"
 	"fmt"
 	"github.com/damejeras/auth/internal/app"
 	"github.com/pkg/errors"
@@ -22,6 +23,13 @@ var validationParams = []string{
 	"nonce",
 }
 
+// verifierParams are appended to authorization request URL when user agent returns from login and consent providers.
+var verifierParams = []string{"login_verifier", "consent_verifier"}
+
+type contextKey int
+
+const requestURLContextKey contextKey = iota
+
 type ValidationError string
 
 func (e ValidationError) Error() string {
@@ -32,6 +40,9 @@ type Footprint struct {
 	RequestID   string
 	RedirectURL string
 	RequestURL  string
+	// Sealed footprint is carried in request URL, see Binder. Request ID and request URL are stored anyway,
+	// sealed footprint is bound to the former and user agent is redirected back to the latter.
+	Sealed bool
 }
 
 func (f *Footprint) Validate(r *http.Request) error {
@@ -54,8 +65,13 @@ func (f *Footprint) Validate(r *http.Request) error {
 		return errors.Wrap(err, "parse request url")
 	}
 
+	requestURL, err := url.Parse(RequestURL(r))
+	if err != nil {
+		return errors.Wrap(err, "parse current request url")
+	}
+
 	footprintValues := footprintURL.Query()
-	requestValues := r.URL.Query()
+	requestValues := requestURL.Query()
 
 	for i := range validationParams {
 		if footprintValues.Get(validationParams[i]) != requestValues.Get(validationParams[i]) {
@@ -65,3 +81,31 @@ func (f *Footprint) Validate(r *http.Request) error {
 
 	return nil
 }
+
+// WithRequestURL overrides URL of authorization request, which footprints are bound to. It is used when
+// authorization parameters are not passed in request URL, so they are not exposed to user agent on redirects.
+func WithRequestURL(r *http.Request, requestURL string) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), requestURLContextKey, requestURL))
+}
+
+// RequestURL returns URL of authorization request, which user agent is redirected back to after login and consent.
+func RequestURL(r *http.Request) string {
+	if requestURL, ok := r.Context().Value(requestURLContextKey).(string); ok {
+		return requestURL
+	}
+
+	// TODO: use r.URL.Scheme ?
+	return "http" + "://" + r.Host + r.URL.RequestURI()
+}
+
+// IsReturning reports whether user agent returns from login or consent provider, so the request is guarded by footprint.
+func IsReturning(r *http.Request) bool {
+	query := r.URL.Query()
+	for i := range verifierParams {
+		if query.Get(verifierParams[i]) != "" {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,147 @@
+package integrity
+
+import (
+	"context"
+	"github.com/damejeras/auth/internal/app"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	pkgErrors "github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Guard locks out subjects, clients and source IP addresses, which caused more violations within the window
+// than configured threshold allows.
+type Guard struct {
+	violationRepository ViolationRepository
+	lockoutRepository   LockoutRepository
+	thresholds          map[string]int
+	window              time.Duration
+	duration            time.Duration
+	logger              *zerolog.Logger
+}
+
+func NewGuard(
+	violationRepository ViolationRepository,
+	lockoutRepository LockoutRepository,
+	cfg *app.Config,
+	logger *zerolog.Logger,
+) *Guard {
+	return &Guard{
+		violationRepository: violationRepository,
+		lockoutRepository:   lockoutRepository,
+		thresholds: map[string]int{
+			LockoutSubject:   cfg.LockoutConfig.SubjectThreshold,
+			LockoutClient:    cfg.LockoutConfig.ClientThreshold,
+			LockoutIPAddress: cfg.LockoutConfig.IPAddressThreshold,
+		},
+		window:   cfg.LockoutConfig.Window,
+		duration: cfg.LockoutConfig.Duration,
+		logger:   logger,
+	}
+}
+
+// Enforce locks out subject, client and source IP address of the violation, which reached their thresholds.
+// Negative threshold disables lockout of its kind.
+// Violations, which caused previous lockout, are not counted again after it ends or is cleared.
+func (g *Guard) Enforce(ctx context.Context, violation *Violation) error {
+	now := time.Now()
+
+	for kind, threshold := range g.thresholds {
+		value := lockoutValue(violation, kind)
+		if value == "" || threshold < 0 {
+			continue
+		}
+
+		lockout, err := g.lockoutRepository.Find(ctx, kind, value)
+		if err != nil {
+			return pkgErrors.Wrapf(err, "find %s %q lockout", kind, value)
+		}
+
+		since := now.Add(-g.window)
+		if lockout != nil {
+			if lockout.Active() {
+				continue
+			}
+
+			if lockout.ExpiresAt.After(since) {
+				since = lockout.ExpiresAt
+			}
+		}
+
+		violations, err := g.violationRepository.CountCreatedAfter(ctx, kind, value, since)
+		if err != nil {
+			return pkgErrors.Wrapf(err, "count %s %q violations", kind, value)
+		}
+
+		if violations < threshold {
+			continue
+		}
+
+		lockout = &Lockout{
+			Kind:       kind,
+			Value:      value,
+			Violations: violations,
+			ExpiresAt:  now.Add(g.duration),
+			CreatedAt:  now,
+		}
+
+		if err := g.lockoutRepository.Store(ctx, lockout); err != nil {
+			return pkgErrors.Wrapf(err, "store %s %q lockout", kind, value)
+		}
+
+		g.logger.Warn().
+			Str("lockout", kind).
+			Str("value", value).
+			Int("violations", violations).
+			Time("expires_at", lockout.ExpiresAt).
+			Msg("lock out after repeated integrity violations")
+	}
+
+	return nil
+}
+
+// Locked returns active lockout of given kind and value, or nil if there is none.
+func (g *Guard) Locked(ctx context.Context, kind, value string) (*Lockout, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	lockout, err := g.lockoutRepository.Find(ctx, kind, value)
+	if err != nil {
+		return nil, pkgErrors.Wrapf(err, "find %s %q lockout", kind, value)
+	}
+
+	if lockout == nil || !lockout.Active() {
+		return nil, nil
+	}
+
+	return lockout, nil
+}
+
+// AuthorizeMiddleware rejects authorization requests of locked out clients and source IP addresses. Subjects are
+// not known until user agent returns from identity provider, so identity manager checks them.
+func (g *Guard) AuthorizeMiddleware(next func(w http.ResponseWriter, r *http.Request) error) func(w http.ResponseWriter, r *http.Request) error {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		for kind, value := range map[string]string{
+			LockoutClient:    r.URL.Query().Get("client_id"),
+			LockoutIPAddress: app.RemoteIP(r),
+		} {
+			lockout, err := g.Locked(r.Context(), kind, value)
+			if err != nil {
+				app.WriteError(w, errors.ErrServerError)
+
+				return err
+			}
+
+			if lockout != nil {
+				w.Header().Set("Retry-After", strconv.Itoa(int(time.Until(lockout.ExpiresAt).Seconds())+1))
+
+				return app.WriteError(w, ErrLockedOut)
+			}
+		}
+
+		return next(w, r)
+	}
+}
@@ -0,0 +1,48 @@
+package integrity
+
+import (
+	"context"
+	"time"
+)
+
+// Kinds of lockouts, each of them is counted from violations of its own attribute.
+const (
+	LockoutSubject   = "subject"
+	LockoutClient    = "client"
+	LockoutIPAddress = "ip_address"
+)
+
+// Lockout temporarily blocks authorization requests of subject, client or source IP address,
+// which caused too many integrity violations.
+type Lockout struct {
+	Kind       string
+	Value      string
+	Violations int
+
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+func (l *Lockout) Active() bool {
+	return time.Now().Before(l.ExpiresAt)
+}
+
+type LockoutRepository interface {
+	Store(ctx context.Context, lockout *Lockout) error
+	Find(ctx context.Context, kind, value string) (*Lockout, error)
+	FindActive(ctx context.Context) ([]*Lockout, error)
+}
+
+// lockoutValue returns violation attribute lockout of given kind is counted by.
+func lockoutValue(violation *Violation, kind string) string {
+	switch kind {
+	case LockoutSubject:
+		return violation.SubjectID
+	case LockoutClient:
+		return violation.ClientID
+	case LockoutIPAddress:
+		return violation.IPAddress
+	default:
+		return ""
+	}
+}
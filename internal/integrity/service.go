@@ -0,0 +1,103 @@
+package integrity
+
+import (
+	"context"
+	"github.com/damejeras/auth/api"
+	"github.com/pkg/errors"
+	"sort"
+	"time"
+)
+
+type service struct {
+	violationRepository ViolationRepository
+	lockoutRepository   LockoutRepository
+}
+
+func NewService(violationRepository ViolationRepository, lockoutRepository LockoutRepository) api.SecurityService {
+	return &service{
+		violationRepository: violationRepository,
+		lockoutRepository:   lockoutRepository,
+	}
+}
+
+func (s *service) ListViolations(ctx context.Context, request api.ListViolationsRequest) (*api.ListViolationsResponse, error) {
+	violations, err := s.violationRepository.FindCreatedAfter(ctx, time.Unix(request.Since, 0))
+	if err != nil {
+		return nil, errors.Wrap(err, "find violations")
+	}
+
+	sort.Slice(violations, func(i, j int) bool {
+		return violations[i].CreatedAt.Before(violations[j].CreatedAt)
+	})
+
+	response := api.ListViolationsResponse{
+		Violations: make([]api.Violation, 0, len(violations)),
+	}
+
+	for _, v := range violations {
+		if (request.Type != "" && v.Type != request.Type) ||
+			(request.ClientID != "" && v.ClientID != request.ClientID) ||
+			(request.SubjectID != "" && v.SubjectID != request.SubjectID) {
+			continue
+		}
+
+		response.Violations = append(response.Violations, api.Violation{
+			ViolationID: v.ID,
+			Type:        v.Type,
+			Description: v.Description,
+			ChallengeID: v.ChallengeID,
+			ClientID:    v.ClientID,
+			SubjectID:   v.SubjectID,
+			RequestID:   v.RequestID,
+			IPAddress:   v.IPAddress,
+			UserAgent:   v.UserAgent,
+			Referer:     v.Referer,
+			CreatedAt:   v.CreatedAt.Unix(),
+		})
+	}
+
+	return &response, nil
+}
+
+func (s *service) ListLockouts(ctx context.Context, _ api.ListLockoutsRequest) (*api.ListLockoutsResponse, error) {
+	lockouts, err := s.lockoutRepository.FindActive(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "find active lockouts")
+	}
+
+	response := api.ListLockoutsResponse{
+		Lockouts: make([]api.Lockout, len(lockouts)),
+	}
+
+	for i := range lockouts {
+		response.Lockouts[i] = api.Lockout{
+			Kind:       lockouts[i].Kind,
+			Value:      lockouts[i].Value,
+			Violations: lockouts[i].Violations,
+			ExpiresAt:  lockouts[i].ExpiresAt.Unix(),
+			CreatedAt:  lockouts[i].CreatedAt.Unix(),
+		}
+	}
+
+	return &response, nil
+}
+
+// ClearLockout ends lockout immediately. Lockout is kept expired, so violations, which caused it, are not counted again.
+func (s *service) ClearLockout(ctx context.Context, request api.ClearLockoutRequest) (*api.ClearLockoutResponse, error) {
+	lockout, err := s.lockoutRepository.Find(ctx, request.Kind, request.Value)
+	if err != nil {
+		return nil, errors.Wrap(err, "find lockout")
+	}
+
+	if lockout == nil || !lockout.Active() {
+		return nil, errors.New("lockout not found")
+	}
+
+	lockout.ExpiresAt = time.Now()
+
+	if err := s.lockoutRepository.Store(ctx, lockout); err != nil {
+		return nil, errors.Wrap(err, "store lockout")
+	}
+
+	return &api.ClearLockoutResponse{}, nil
+}
@@ -0,0 +1,103 @@
+package integrity
+
+import (
+	"context"
+	"expvar"
+	"github.com/damejeras/auth/internal/app"
+	"github.com/rs/zerolog"
+	"github.com/segmentio/ksuid"
+	"net/http"
+	"time"
+)
+
+// Types of authorization request integrity violations.
+const (
+	// ViolationFootprintMismatch means request returning from login or consent provider does not match its footprint,
+	// e.g. verifier is replayed from another user agent or the request is forged.
+	ViolationFootprintMismatch = "footprint_mismatch"
+	// ViolationUnknownVerifier means login or consent verifier does not exist or has already been used.
+	ViolationUnknownVerifier = "unknown_verifier"
+	// ViolationUnauthenticatedChallenge means user agent returned with login verifier before identity provider authenticated the user.
+	ViolationUnauthenticatedChallenge = "unauthenticated_challenge"
+	// ViolationUngrantedChallenge means user agent returned with consent verifier before consent provider granted consent.
+	ViolationUngrantedChallenge = "ungranted_challenge"
+)
+
+// violationCounts counts tracked violations by type, it is published with other runtime metrics by admin server.
+var violationCounts = expvar.NewMap("integrity_violations")
+
+// Violation is recorded attempt to break integrity of authorization request.
+type Violation struct {
+	ID          string
+	Type        string
+	Description string
+	ChallengeID string
+	ClientID    string
+	SubjectID   string
+	RequestID   string
+	IPAddress   string
+	UserAgent   string
+	Referer     string
+	// ExpiresAt is when violation is deleted, it is zero for violations recorded before retention was introduced.
+	ExpiresAt time.Time
+
+	CreatedAt time.Time
+}
+
+type ViolationRepository interface {
+	Store(ctx context.Context, violation *Violation) error
+	FindCreatedAfter(ctx context.Context, since time.Time) ([]*Violation, error)
+	// CountCreatedAfter counts violations, which attribute of given lockout kind has given value.
+	CountCreatedAfter(ctx context.Context, kind, value string, since time.Time) (int, error)
+}
+
+// Tracker records integrity violations, so replay and CSRF attempts can be detected, and lets guard
+// lock out their sources.
+type Tracker struct {
+	repository ViolationRepository
+	guard      *Guard
+	retention  time.Duration
+	logger     *zerolog.Logger
+}
+
+func NewTracker(repository ViolationRepository, guard *Guard, cfg *app.Config, logger *zerolog.Logger) *Tracker {
+	return &Tracker{
+		repository: repository,
+		guard:      guard,
+		retention:  cfg.IntegrityConfig.ViolationRetention,
+		logger:     logger,
+	}
+}
+
+// Track completes violation with request details, logs, counts and stores it, and locks out its sources if they reached
+// thresholds. Failures are only logged, as the request is rejected anyway.
+func (t *Tracker) Track(r *http.Request, violation Violation) {
+	violation.ID = ksuid.New().String()
+	violation.RequestID = app.GetCurrentRequestID(r)
+	violation.IPAddress = app.RemoteIP(r)
+	violation.UserAgent = r.UserAgent()
+	violation.Referer = r.Referer()
+	violation.CreatedAt = time.Now()
+	violation.ExpiresAt = violation.CreatedAt.Add(t.retention)
+
+	t.logger.Warn().
+		Str("violation", violation.Type).
+		Str("challenge_id", violation.ChallengeID).
+		Str("client_id", violation.ClientID).
+		Str("subject_id", violation.SubjectID).
+		Str("request_id", violation.RequestID).
+		Str("ip_address", violation.IPAddress).
+		Msg(violation.Description)
+
+	violationCounts.Add(violation.Type, 1)
+
+	if err := t.repository.Store(r.Context(), &violation); err != nil {
+		t.logger.Error().Err(err).Msgf("store integrity violation %q", violation.ID)
+
+		return
+	}
+
+	if err := t.guard.Enforce(r.Context(), &violation); err != nil {
+		t.logger.Error().Err(err).Msgf("enforce lockout for integrity violation %q", violation.ID)
+	}
+}
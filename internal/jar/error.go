@@ -0,0 +1,22 @@
+package jar
+
+import (
+	stdErrors "errors"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"net/http"
+)
+
+// Authorization endpoint errors defined by RFC 9101 section 6.
+var (
+	ErrInvalidRequestObject = stdErrors.New("invalid_request_object")
+	ErrInvalidRequestURI    = stdErrors.New("invalid_request_uri")
+)
+
+// register errors with oauth2 server, so they are rendered as regular oauth2 errors.
+func init() {
+	errors.Descriptions[ErrInvalidRequestObject] = "The request parameter contains an invalid request object"
+	errors.Descriptions[ErrInvalidRequestURI] = "The request_uri in the authorization request returns an error or contains invalid data"
+
+	errors.StatusCodes[ErrInvalidRequestObject] = http.StatusBadRequest
+	errors.StatusCodes[ErrInvalidRequestURI] = http.StatusBadRequest
+}
@@ -0,0 +1,238 @@
+package jar
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/damejeras/auth/internal/app"
+	"github.com/damejeras/auth/internal/client"
+	"github.com/damejeras/auth/internal/integrity"
+	"github.com/damejeras/auth/internal/signing"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/golang-jwt/jwt"
+	pkgErrors "github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	paramRequest    = "request"
+	paramRequestURI = "request_uri"
+
+	requestObjectMediaType = "application/oauth-authz-req+jwt"
+	maxRequestObjectSize   = 64 << 10
+	fetchTimeout           = 5 * time.Second
+)
+
+var validMethods = []string{
+	jwt.SigningMethodRS256.Alg(),
+	jwt.SigningMethodRS384.Alg(),
+	jwt.SigningMethodRS512.Alg(),
+	jwt.SigningMethodPS256.Alg(),
+	jwt.SigningMethodPS384.Alg(),
+	jwt.SigningMethodPS512.Alg(),
+	jwt.SigningMethodES256.Alg(),
+	jwt.SigningMethodES384.Alg(),
+	jwt.SigningMethodES512.Alg(),
+}
+
+// registered claims describe request object itself, they are not authorization parameters.
+var registeredClaims = []string{"iss", "aud", "exp", "iat", "nbf", "jti", paramRequest, paramRequestURI}
+
+// Handler resolves JWT-secured authorization requests defined by RFC 9101.
+type Handler struct {
+	clientStorage *client.Storage
+	signer        *signing.Signer
+	httpClient    *http.Client
+	logger        *zerolog.Logger
+}
+
+func NewHandler(clientStorage *client.Storage, signer *signing.Signer, logger *zerolog.Logger) *Handler {
+	return &Handler{
+		clientStorage: clientStorage,
+		signer:        signer,
+		httpClient:    &http.Client{Timeout: fetchTimeout},
+		logger:        logger,
+	}
+}
+
+// AuthorizeMiddleware verifies request object passed by value or by reference at authorization endpoint
+// and replaces query parameters with its claims, so login challenge is created from signed parameters only.
+func (h *Handler) AuthorizeMiddleware(next func(w http.ResponseWriter, r *http.Request) error) func(w http.ResponseWriter, r *http.Request) error {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		query := r.URL.Query()
+
+		cl, err := h.clientStorage.FindByID(r.Context(), query.Get("client_id"))
+		if err != nil {
+			app.WriteError(w, errors.ErrServerError)
+
+			return pkgErrors.Wrapf(err, "find client %q", query.Get("client_id"))
+		}
+
+		requestObject := query.Get(paramRequest)
+		if requestURI := query.Get(paramRequestURI); requestObject == "" && requestURI != "" {
+			if cl == nil || !cl.HasRequestURI(requestURI) {
+				return h.clientStorage.WriteAuthorizeError(w, r, ErrInvalidRequestURI)
+			}
+
+			requestObject, err = h.fetch(r.Context(), requestURI)
+			if err != nil {
+				h.logger.Debug().Err(err).Msgf("fetch request object from %q", requestURI)
+
+				return h.clientStorage.WriteAuthorizeError(w, r, ErrInvalidRequestURI)
+			}
+		}
+
+		if requestObject == "" {
+			if cl != nil && cl.RequireSignedRequestObject {
+				return app.WriteError(w, errors.ErrInvalidRequest)
+			}
+
+			return next(w, r)
+		}
+
+		if cl == nil {
+			return app.WriteError(w, errors.ErrInvalidClient)
+		}
+
+		claims, err := h.verify(cl, requestObject, !integrity.IsReturning(r))
+		if err != nil {
+			h.logger.Debug().Err(err).Msgf("verify client's %q request object", cl.ID)
+
+			return h.clientStorage.WriteAuthorizeError(w, r, ErrInvalidRequestObject)
+		}
+
+		// only request object parameters are used, parameters outside of it are not signed by client
+		values := make(url.Values, len(claims))
+		for _, key := range append([]string{"client_id", paramRequest, paramRequestURI}, integrity.ReturnParams()...) {
+			if value, ok := query[key]; ok {
+				values[key] = value
+			}
+		}
+
+		for i := range registeredClaims {
+			delete(claims, registeredClaims[i])
+		}
+
+		for key, claim := range claims {
+			value, err := claimValue(claim)
+			if err != nil {
+				return h.clientStorage.WriteAuthorizeError(w, r, ErrInvalidRequestObject)
+			}
+
+			values.Set(key, value)
+		}
+
+		resolved := integrity.WithRequestURL(r.Clone(r.Context()), integrity.RequestURL(r))
+		resolved.URL.RawQuery = values.Encode()
+		resolved.Form = nil
+
+		return next(w, resolved)
+	}
+}
+
+// verify decrypts request object if it is encrypted and checks its signature with client's key.
+// Request object must be issued by client, intended for this server and expire, so it can not be replayed.
+// Expiration is checked only when authorization starts, as login and consent can take longer than request object lives.
+func (h *Handler) verify(cl *client.Client, requestObject string, checkExpiration bool) (jwt.MapClaims, error) {
+	if signing.IsEncrypted(requestObject) {
+		decrypted, err := h.signer.Decrypt(requestObject)
+		if err != nil {
+			return nil, pkgErrors.Wrap(err, "decrypt request object")
+		}
+
+		requestObject = string(decrypted)
+	}
+
+	claims := make(jwt.MapClaims)
+	parser := jwt.Parser{
+		ValidMethods:         validMethods,
+		SkipClaimsValidation: true,
+	}
+
+	if _, err := parser.ParseWithClaims(requestObject, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+
+		key := cl.KeySet.Key(kid)
+		if key == nil {
+			return nil, pkgErrors.Errorf("key %q not found", kid)
+		}
+
+		return key.PublicKey()
+	}); err != nil {
+		return nil, pkgErrors.Wrap(err, "parse request object")
+	}
+
+	if _, ok := claims["exp"]; !ok {
+		return nil, pkgErrors.New("request object does not expire")
+	}
+
+	now := time.Now().Unix()
+	if checkExpiration && (!claims.VerifyExpiresAt(now, true) || !claims.VerifyNotBefore(now, false)) {
+		return nil, pkgErrors.New("request object is expired")
+	}
+
+	if !claims.VerifyIssuer(cl.ID, true) {
+		return nil, pkgErrors.New("request object is not issued by client")
+	}
+
+	if !claims.VerifyAudience(h.signer.Issuer(), true) {
+		return nil, pkgErrors.New("request object is not intended for this server")
+	}
+
+	if clientID, ok := claims["client_id"]; ok && clientID != cl.ID {
+		return nil, pkgErrors.New("request object client ID does not match")
+	}
+
+	return claims, nil
+}
+
+func (h *Handler) fetch(ctx context.Context, requestURI string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURI, nil)
+	if err != nil {
+		return "", pkgErrors.Wrap(err, "create request")
+	}
+
+	req.Header.Set("Accept", requestObjectMediaType)
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return "", pkgErrors.Wrap(err, "execute request")
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", pkgErrors.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(nil, resp.Body, maxRequestObjectSize))
+	if err != nil {
+		return "", pkgErrors.Wrap(err, "read response body")
+	}
+
+	return string(body), nil
+}
+
+// claimValue converts request object claim to authorization parameter value.
+// Structured claims, such as claims request or authorization details, are passed as JSON.
+func claimValue(claim interface{}) (string, error) {
+	switch value := claim.(type) {
+	case string:
+		return value, nil
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(value), nil
+	default:
+		valueBytes, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+
+		return string(valueBytes), nil
+	}
+}
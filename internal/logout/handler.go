@@ -0,0 +1,245 @@
+package logout
+
+import (
+	"github.com/damejeras/auth/internal/client"
+	"github.com/damejeras/auth/internal/session"
+	"github.com/damejeras/auth/internal/signing"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"html/template"
+	"net/http"
+	"net/url"
+)
+
+const (
+	paramClientID              = "client_id"
+	paramPostLogoutRedirectURI = "post_logout_redirect_uri"
+	paramState                 = "state"
+	paramCSRFToken             = "csrf_token"
+)
+
+// frontchannelPage renders invisible iframes with clients' front-channel logout URIs
+// and redirects user agent to post logout redirect URI once all of them are loaded.
+var frontchannelPage = template.Must(template.New("frontchannel").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Logout</title></head>
+<body>
+<p>You have been logged out.</p>
+{{range .LogoutURLs}}<iframe src="{{.}}" style="display:none"></iframe>
+{{end}}{{if .RedirectURL}}<script>window.onload = function () { window.location.href = {{.RedirectURL}}; };</script>
+{{end}}</body>
+</html>
+`))
+
+// confirmationPage asks user to confirm logout, so other sites can not log user out.
+var confirmationPage = template.Must(template.New("confirmation").Parse(`<!DOCTYPE html>
+<html>
+<head><title>Logout</title></head>
+<body>
+<form method="post" action="{{.Action}}">
+<p>Do you want to log out?</p>
+{{range $name, $value := .Fields}}<input type="hidden" name="{{$name}}" value="{{$value}}">
+{{end}}<button type="submit">Log out</button>
+</form>
+</body>
+</html>
+`))
+
+// Handler serves OpenID Connect RP-Initiated Logout 1.0 requests. Server does not issue ID tokens, so id_token_hint
+// is not supported and ignored, client is identified by client_id and every logout is confirmed by user.
+type Handler struct {
+	sessionRepository session.Repository
+	sessionCookie     *session.Cookie
+	clientStorage     *client.Storage
+	signer            *signing.Signer
+	notifier          *Notifier
+	logger            *zerolog.Logger
+}
+
+func NewHandler(
+	sessionRepository session.Repository,
+	sessionCookie *session.Cookie,
+	clientStorage *client.Storage,
+	signer *signing.Signer,
+	notifier *Notifier,
+	logger *zerolog.Logger,
+) *Handler {
+	return &Handler{
+		sessionRepository: sessionRepository,
+		sessionCookie:     sessionCookie,
+		clientStorage:     clientStorage,
+		signer:            signer,
+		notifier:          notifier,
+		logger:            logger,
+	}
+}
+
+func (h *Handler) HandleLogoutRequest(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+
+		return nil
+	}
+
+	clientID := r.FormValue(paramClientID)
+
+	redirectURI := r.FormValue(paramPostLogoutRedirectURI)
+	if redirectURI != "" {
+		if clientID == "" {
+			http.Error(w, "post_logout_redirect_uri requires client_id", http.StatusBadRequest)
+
+			return nil
+		}
+
+		cl, err := h.clientStorage.FindByID(r.Context(), clientID)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+			return errors.Wrap(err, "find client")
+		}
+
+		if cl == nil || !cl.HasPostLogoutRedirectURI(redirectURI) {
+			http.Error(w, "post_logout_redirect_uri is not registered", http.StatusBadRequest)
+
+			return nil
+		}
+	}
+
+	sess, err := session.FromRequest(r, h.sessionCookie, h.sessionRepository)
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+		return errors.Wrap(err, "find current session")
+	}
+
+	// user agent could be sent here by any site, so user confirms logout with form bound to the session
+	confirmed := r.Method == http.MethodPost && sess != nil &&
+		h.sessionCookie.VerifyCSRFToken(sess, r.PostFormValue(paramCSRFToken))
+
+	if sess != nil && !confirmed {
+		return h.confirm(w, r, sess, clientID, redirectURI)
+	}
+
+	var frontchannelURLs []string
+
+	if sess != nil {
+		if err := h.sessionRepository.Delete(r.Context(), sess); err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+			return errors.Wrap(err, "delete session")
+		}
+
+		h.sessionCookie.Clear(w)
+
+		if err := h.notifier.NotifyLogout(r.Context(), sess); err != nil {
+			h.logger.Error().Err(err).Msgf("notify clients about session %q logout", sess.ID)
+		}
+
+		frontchannelURLs, err = h.frontchannelURLs(r, sess)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+			return errors.Wrap(err, "build front-channel logout urls")
+		}
+	}
+
+	var location *url.URL
+	if redirectURI != "" {
+		location, err = url.Parse(redirectURI)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+
+			return errors.Wrap(err, "parse post logout redirect uri")
+		}
+
+		if state := r.FormValue(paramState); state != "" {
+			query := location.Query()
+			query.Set(paramState, state)
+			location.RawQuery = query.Encode()
+		}
+	}
+
+	if len(frontchannelURLs) == 0 && location != nil {
+		w.Header().Set("Location", location.String())
+		w.WriteHeader(http.StatusFound)
+
+		return nil
+	}
+
+	data := struct {
+		LogoutURLs  []string
+		RedirectURL string
+	}{
+		LogoutURLs: frontchannelURLs,
+	}
+
+	if location != nil {
+		data.RedirectURL = location.String()
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+
+	return frontchannelPage.Execute(w, data)
+}
+
+// confirm renders logout confirmation form, which repeats logout request with CSRF token of the session.
+func (h *Handler) confirm(w http.ResponseWriter, r *http.Request, sess *session.Session, clientID, redirectURI string) error {
+	fields := map[string]string{
+		paramCSRFToken: h.sessionCookie.CSRFToken(sess),
+	}
+
+	if clientID != "" {
+		fields[paramClientID] = clientID
+	}
+
+	if redirectURI != "" {
+		fields[paramPostLogoutRedirectURI] = redirectURI
+	}
+
+	if state := r.FormValue(paramState); state != "" {
+		fields[paramState] = state
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("X-Frame-Options", "DENY")
+
+	return confirmationPage.Execute(w, struct {
+		Action string
+		Fields map[string]string
+	}{
+		Action: r.URL.Path,
+		Fields: fields,
+	})
+}
+
+// frontchannelURLs returns front-channel logout URIs of session clients with issuer and session ID attached.
+func (h *Handler) frontchannelURLs(r *http.Request, sess *session.Session) ([]string, error) {
+	var result []string
+
+	for i := range sess.ClientIDs {
+		cl, err := h.clientStorage.FindByID(r.Context(), sess.ClientIDs[i])
+		if err != nil {
+			return nil, errors.Wrapf(err, "find client %q", sess.ClientIDs[i])
+		}
+
+		if cl == nil || cl.FrontchannelLogoutURI == "" {
+			continue
+		}
+
+		logoutURL, err := url.Parse(cl.FrontchannelLogoutURI)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse client's %q front-channel logout uri", cl.ID)
+		}
+
+		query := logoutURL.Query()
+		query.Set("iss", h.signer.Issuer())
+		query.Set("sid", sess.ID)
+		logoutURL.RawQuery = query.Encode()
+
+		result = append(result, logoutURL.String())
+	}
+
+	return result, nil
+}
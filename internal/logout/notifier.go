@@ -0,0 +1,125 @@
+package logout
+
+import (
+	"context"
+	"github.com/damejeras/auth/internal/app"
+	"github.com/damejeras/auth/internal/client"
+	"github.com/damejeras/auth/internal/session"
+	"github.com/damejeras/auth/internal/signing"
+	"github.com/golang-jwt/jwt"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/segmentio/ksuid"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const backchannelLogoutEvent = "http://schemas.openid.net/event/backchannel-logout"
+
+type logoutTokenClaims struct {
+	jwt.StandardClaims
+	SessionID string              `json:"sid"`
+	Events    map[string]struct{} `json:"events"`
+}
+
+// Notifier propagates session termination to clients authorized during the session using back-channel logout.
+type Notifier struct {
+	clientStorage *client.Storage
+	signer        *signing.Signer
+	httpClient    *http.Client
+	retries       int
+	retryInterval time.Duration
+	logger        *zerolog.Logger
+}
+
+func NewNotifier(clientStorage *client.Storage, signer *signing.Signer, cfg *app.Config, logger *zerolog.Logger) *Notifier {
+	return &Notifier{
+		clientStorage: clientStorage,
+		signer:        signer,
+		httpClient:    &http.Client{Timeout: cfg.LogoutConfig.Timeout},
+		retries:       cfg.LogoutConfig.Retries,
+		retryInterval: cfg.LogoutConfig.RetryInterval,
+		logger:        logger,
+	}
+}
+
+// NotifyLogout signs logout tokens for session clients with registered back-channel logout URI
+// and delivers them in background, so session termination is not blocked by slow clients.
+func (n *Notifier) NotifyLogout(ctx context.Context, sess *session.Session) error {
+	for i := range sess.ClientIDs {
+		cl, err := n.clientStorage.FindByID(ctx, sess.ClientIDs[i])
+		if err != nil {
+			return errors.Wrapf(err, "find client %q", sess.ClientIDs[i])
+		}
+
+		if cl == nil || cl.BackchannelLogoutURI == "" {
+			continue
+		}
+
+		logoutToken, err := n.signer.Sign(signing.TypeLogoutToken, logoutTokenClaims{
+			StandardClaims: jwt.StandardClaims{
+				Audience: cl.ID,
+				Id:       ksuid.New().String(),
+				IssuedAt: time.Now().Unix(),
+				Issuer:   n.signer.Issuer(),
+				Subject:  sess.SubjectID,
+			},
+			SessionID: sess.ID,
+			Events:    map[string]struct{}{backchannelLogoutEvent: {}},
+		})
+		if err != nil {
+			return errors.Wrap(err, "sign logout token")
+		}
+
+		go n.deliver(cl.BackchannelLogoutURI, logoutToken)
+	}
+
+	return nil
+}
+
+func (n *Notifier) deliver(logoutURI, logoutToken string) {
+	body := url.Values{"logout_token": {logoutToken}}.Encode()
+	interval := n.retryInterval
+
+	for attempt := 0; ; attempt++ {
+		err := n.post(logoutURI, body)
+		if err == nil {
+			return
+		}
+
+		if attempt >= n.retries {
+			n.logger.Error().Err(err).Msgf("deliver logout token to %q", logoutURI)
+
+			return
+		}
+
+		n.logger.Warn().Err(err).Msgf("deliver logout token to %q, retrying in %s", logoutURI, interval)
+
+		time.Sleep(interval)
+		interval *= 2
+	}
+}
+
+func (n *Notifier) post(logoutURI, body string) error {
+	request, err := http.NewRequest(http.MethodPost, logoutURI, strings.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "create request")
+	}
+
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	response, err := n.httpClient.Do(request)
+	if err != nil {
+		return errors.Wrap(err, "execute request")
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusNoContent {
+		return errors.Errorf("unexpected status code %d", response.StatusCode)
+	}
+
+	return nil
+}
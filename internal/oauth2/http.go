@@ -2,15 +2,48 @@ package oauth2
 
 import (
 	"github.com/damejeras/auth/internal/app"
+	"github.com/damejeras/auth/internal/ciba"
+	"github.com/damejeras/auth/internal/device"
+	"github.com/damejeras/auth/internal/integrity"
+	"github.com/damejeras/auth/internal/jar"
+	"github.com/damejeras/auth/internal/logout"
+	"github.com/damejeras/auth/internal/par"
+	"github.com/damejeras/auth/internal/rar"
+	"github.com/damejeras/auth/internal/resource"
+	"github.com/damejeras/auth/internal/scope"
+	"github.com/damejeras/auth/internal/session"
+	"github.com/damejeras/auth/internal/signing"
 	"github.com/go-oauth2/oauth2/v4/server"
 	"github.com/rs/zerolog"
 	"net/http"
 )
 
-func NewHTTPServer(server *server.Server, logger *zerolog.Logger) *http.Server {
+func NewHTTPServer(
+	server *server.Server,
+	tokenHandler *TokenHandler,
+	introspectionHandler *IntrospectionHandler,
+	deviceHandler *device.Handler,
+	cibaHandler *ciba.Handler,
+	parHandler *par.Handler,
+	jarHandler *jar.Handler,
+	resourceHandler *resource.Handler,
+	scopeHandler *scope.Handler,
+	rarHandler *rar.Handler,
+	integrityGuard *integrity.Guard,
+	logoutHandler *logout.Handler,
+	signer *signing.Signer,
+	logger *zerolog.Logger,
+) *http.Server {
 	mux := http.NewServeMux()
-	mux.Handle("/authorize", app.RequestMiddleware(requestLogger(logger)(server.HandleAuthorizeRequest)))
-	mux.Handle("/token", app.RequestMiddleware(requestLogger(logger)(server.HandleTokenRequest)))
+	mux.Handle("/authorize", app.RequestMiddleware(requestLogger(logger)(integrityGuard.AuthorizeMiddleware(parHandler.AuthorizeMiddleware(jarHandler.AuthorizeMiddleware(resourceHandler.AuthorizeMiddleware(scopeHandler.AuthorizeMiddleware(rarHandler.AuthorizeMiddleware(session.AuthorizeMiddleware(server.HandleAuthorizeRequest))))))))))
+	mux.Handle("/par", app.RequestMiddleware(requestLogger(logger)(parHandler.HandlePushedAuthorizationRequest)))
+	mux.Handle("/token", app.RequestMiddleware(requestLogger(logger)(tokenHandler.HandleTokenRequest)))
+	mux.Handle("/introspect", app.RequestMiddleware(requestLogger(logger)(introspectionHandler.HandleIntrospectionRequest)))
+	mux.Handle("/device_authorization", app.RequestMiddleware(requestLogger(logger)(deviceHandler.HandleDeviceAuthorizationRequest)))
+	mux.Handle("/device", app.RequestMiddleware(requestLogger(logger)(deviceHandler.HandleVerificationRequest)))
+	mux.Handle("/bc-authorize", app.RequestMiddleware(requestLogger(logger)(cibaHandler.HandleBackchannelAuthenticationRequest)))
+	mux.Handle("/logout", app.RequestMiddleware(requestLogger(logger)(logoutHandler.HandleLogoutRequest)))
+	mux.Handle("/.well-known/jwks.json", app.RequestMiddleware(requestLogger(logger)(signer.HandleKeySetRequest)))
 
 	return &http.Server{
 		Handler: mux,
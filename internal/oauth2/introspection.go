@@ -0,0 +1,156 @@
+package oauth2
+
+import (
+	"context"
+	"github.com/damejeras/auth/internal/client"
+	"github.com/damejeras/auth/internal/dpop"
+	"github.com/damejeras/auth/pkg/dynamo"
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/server"
+	pkgErrors "github.com/pkg/errors"
+	"net/http"
+	"time"
+)
+
+const tokenTypeHintRefreshToken = "refresh_token"
+
+// IntrospectionHandler serves token introspection endpoint defined by RFC 7662.
+type IntrospectionHandler struct {
+	server        *server.Server
+	tokenStore    dynamo.TokenStore
+	clientStorage *client.Storage
+}
+
+func NewIntrospectionHandler(server *server.Server, tokenStore dynamo.TokenStore, clientStorage *client.Storage) *IntrospectionHandler {
+	return &IntrospectionHandler{
+		server:        server,
+		tokenStore:    tokenStore,
+		clientStorage: clientStorage,
+	}
+}
+
+func (h *IntrospectionHandler) HandleIntrospectionRequest(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodPost {
+		return h.introspectionError(w, errors.ErrInvalidRequest)
+	}
+
+	clientID, clientSecret, err := h.server.ClientInfoHandler(r)
+	if err != nil {
+		return h.introspectionError(w, err)
+	}
+
+	cl, err := h.clientStorage.FindByID(r.Context(), clientID)
+	if err != nil {
+		h.introspectionError(w, errors.ErrServerError)
+
+		return pkgErrors.Wrapf(err, "find client %q", clientID)
+	}
+
+	if cl == nil || cl.Secret != clientSecret {
+		return h.introspectionError(w, errors.ErrInvalidClient)
+	}
+
+	value := r.FormValue("token")
+	if value == "" {
+		return h.introspectionError(w, errors.ErrInvalidRequest)
+	}
+
+	data, err := h.introspect(r.Context(), value, r.FormValue("token_type_hint"))
+	if err != nil {
+		h.introspectionError(w, errors.ErrServerError)
+
+		return pkgErrors.Wrap(err, "introspect token")
+	}
+
+	return writeJSON(w, data, nil, http.StatusOK)
+}
+
+// introspect looks token up as the hinted type first and falls back to the other one, as RFC 7662 requires.
+func (h *IntrospectionHandler) introspect(ctx context.Context, value, hint string) (map[string]interface{}, error) {
+	lookups := []func(context.Context, string) (map[string]interface{}, error){h.introspectAccess, h.introspectRefresh}
+	if hint == tokenTypeHintRefreshToken {
+		lookups[0], lookups[1] = lookups[1], lookups[0]
+	}
+
+	for i := range lookups {
+		data, err := lookups[i](ctx, value)
+		if err != nil || data != nil {
+			return data, err
+		}
+	}
+
+	return map[string]interface{}{"active": false}, nil
+}
+
+func (h *IntrospectionHandler) introspectAccess(ctx context.Context, access string) (map[string]interface{}, error) {
+	ti, err := h.tokenStore.GetByAccess(ctx, access)
+	if err != nil {
+		return nil, pkgErrors.Wrap(err, "find token by access")
+	}
+
+	if ti == nil || ti.GetAccess() != access {
+		return nil, nil
+	}
+
+	return introspectionData(ti, ti.GetAccessCreateAt(), ti.GetAccessExpiresIn()), nil
+}
+
+func (h *IntrospectionHandler) introspectRefresh(ctx context.Context, refresh string) (map[string]interface{}, error) {
+	ti, err := h.tokenStore.GetByRefresh(ctx, refresh)
+	if err != nil {
+		return nil, pkgErrors.Wrap(err, "find token by refresh")
+	}
+
+	if ti == nil || ti.GetRefresh() != refresh {
+		return nil, nil
+	}
+
+	return introspectionData(ti, ti.GetRefreshCreateAt(), ti.GetRefreshExpiresIn()), nil
+}
+
+func introspectionData(ti oauth2.TokenInfo, createdAt time.Time, expiresIn time.Duration) map[string]interface{} {
+	expiresAt := createdAt.Add(expiresIn)
+	if expiresIn > 0 && expiresAt.Before(time.Now()) {
+		return map[string]interface{}{"active": false}
+	}
+
+	data := map[string]interface{}{
+		"active":     true,
+		"client_id":  ti.GetClientID(),
+		"token_type": "Bearer",
+		"iat":        createdAt.Unix(),
+	}
+
+	if expiresIn > 0 {
+		data["exp"] = expiresAt.Unix()
+	}
+
+	if scope := ti.GetScope(); scope != "" {
+		data["scope"] = scope
+	}
+
+	if userID := ti.GetUserID(); userID != "" {
+		data["sub"] = userID
+	}
+
+	if token, ok := ti.(*dynamo.Token); ok {
+		if dpop.Thumbprint(token.Extension) != "" {
+			data["token_type"] = dpop.TokenType
+		}
+
+		for key, value := range token.Extension {
+			if _, ok := data[key]; !ok {
+				data[key] = value
+			}
+		}
+	}
+
+	return data
+}
+
+func (h *IntrospectionHandler) introspectionError(w http.ResponseWriter, err error) error {
+	data, statusCode, header := h.server.GetErrorData(err)
+
+	return writeJSON(w, data, header, statusCode)
+}
@@ -13,6 +13,8 @@ func NewServer(manager *manage.Manager, identityManager *identity.Manager) *serv
 	srv.SetAllowedGrantType(oauth2.AuthorizationCode, oauth2.ClientCredentials)
 	srv.SetClientInfoHandler(server.ClientBasicHandler)
 	srv.SetUserAuthorizationHandler(identityManager.UserAuthorizationHandler())
+	srv.SetAuthorizeScopeHandler(identityManager.AuthorizeScopeHandler())
+	srv.SetInternalErrorHandler(identityManager.InternalErrorHandler())
 
 	return srv
 }
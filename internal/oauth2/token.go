@@ -0,0 +1,401 @@
+package oauth2
+
+import (
+	"encoding/json"
+	"github.com/damejeras/auth/internal/assertion"
+	"github.com/damejeras/auth/internal/ciba"
+	"github.com/damejeras/auth/internal/client"
+	"github.com/damejeras/auth/internal/consent"
+	"github.com/damejeras/auth/internal/device"
+	"github.com/damejeras/auth/internal/dpop"
+	"github.com/damejeras/auth/internal/exchange"
+	"github.com/damejeras/auth/internal/rar"
+	"github.com/damejeras/auth/internal/resource"
+	"github.com/damejeras/auth/internal/session"
+	"github.com/damejeras/auth/pkg/dynamo"
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/generates"
+	"github.com/go-oauth2/oauth2/v4/manage"
+	"github.com/go-oauth2/oauth2/v4/server"
+	pkgErrors "github.com/pkg/errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Grant resolves token request of extension grant type, which is not supported by oauth2 server.
+// It is expected to fill in token subject and scope, optionally attach extension claims to the token,
+// or return oauth2 error.
+type Grant interface {
+	TokenConfig() *manage.Config
+	ResolveTokenRequest(tgr *oauth2.TokenGenerateRequest, token *dynamo.Token) error
+}
+
+// responseExtender is implemented by grants, which add their own parameters to token response.
+type responseExtender interface {
+	ExtendTokenResponse(data map[string]interface{})
+}
+
+// TokenHandler serves token endpoint. Extension grants are handled here, others are passed to oauth2 server.
+type TokenHandler struct {
+	server          *server.Server
+	tokenStore      dynamo.TokenStore
+	clientStorage   *client.Storage
+	proofVerifier   *dpop.Verifier
+	resourceStorage *resource.Storage
+	rarHandler      *rar.Handler
+	accessGenerate  oauth2.AccessGenerate
+	grants          map[oauth2.GrantType]Grant
+}
+
+func NewTokenHandler(
+	server *server.Server,
+	tokenStore dynamo.TokenStore,
+	clientStorage *client.Storage,
+	proofVerifier *dpop.Verifier,
+	resourceStorage *resource.Storage,
+	rarHandler *rar.Handler,
+	deviceHandler *device.Handler,
+	cibaHandler *ciba.Handler,
+	exchangeGrant *exchange.Grant,
+	assertionGrant *assertion.Grant,
+) *TokenHandler {
+	return &TokenHandler{
+		server:          server,
+		tokenStore:      tokenStore,
+		clientStorage:   clientStorage,
+		proofVerifier:   proofVerifier,
+		resourceStorage: resourceStorage,
+		rarHandler:      rarHandler,
+		accessGenerate:  generates.NewAccessGenerate(),
+		grants: map[oauth2.GrantType]Grant{
+			device.GrantType:    deviceHandler,
+			ciba.GrantType:      cibaHandler,
+			exchange.GrantType:  exchangeGrant,
+			assertion.GrantType: assertionGrant,
+		},
+	}
+}
+
+func (h *TokenHandler) HandleTokenRequest(w http.ResponseWriter, r *http.Request) error {
+	grantToken, err := h.loadGrantToken(r)
+	if err != nil {
+		h.tokenError(w, errors.ErrServerError)
+
+		return pkgErrors.Wrap(err, "load grant token")
+	}
+
+	// response parameters, which oauth2 server does not know about
+	response := make(map[string]interface{})
+
+	thumbprint, err := h.proofVerifier.Verify(r)
+	if err != nil {
+		if _, ok := errors.Descriptions[err]; ok {
+			return h.tokenError(w, err)
+		}
+
+		h.tokenError(w, errors.ErrServerError)
+
+		return pkgErrors.Wrap(err, "verify DPoP proof")
+	}
+
+	// refresh token bound to a key can be used only with proof signed by the same key
+	if grantToken != nil && grantToken.GetRefresh() != "" {
+		if bound := dpop.Thumbprint(grantToken.Extension); bound != "" && bound != thumbprint {
+			return h.tokenError(w, dpop.ErrInvalidDPoPProof)
+		}
+	}
+
+	// tokens issued for code or refresh token belong to the same session
+	if grantToken != nil {
+		if sessionID := session.ID(grantToken); sessionID != "" {
+			r = r.WithContext(dynamo.WithExtension(r.Context(), dynamo.ClaimSessionID, sessionID))
+		}
+	}
+
+	// token store binds every token created while serving the request to the proof key
+	if thumbprint != "" {
+		r = r.WithContext(dynamo.WithExtension(r.Context(), dpop.ClaimConfirmation, dpop.Confirmation(thumbprint)))
+		response["token_type"] = dpop.TokenType
+	}
+
+	r, resources, err := h.resolveResources(r, grantToken)
+	if err != nil {
+		if _, ok := errors.Descriptions[err]; ok {
+			return h.tokenError(w, err)
+		}
+
+		h.tokenError(w, errors.ErrServerError)
+
+		return pkgErrors.Wrap(err, "resolve requested resources")
+	}
+
+	r, details, err := h.resolveAuthorizationDetails(r, grantToken)
+	if err != nil {
+		h.tokenError(w, errors.ErrServerError)
+
+		return pkgErrors.Wrap(err, "resolve authorization details")
+	}
+
+	if details != nil {
+		response[rar.ClaimAuthorizationDetails] = details
+	}
+
+	grant, ok := h.grants[oauth2.GrantType(r.FormValue("grant_type"))]
+	if !ok {
+		if len(response) == 0 {
+			return h.server.HandleTokenRequest(w, r)
+		}
+
+		return h.handleServerTokenRequest(w, r, response)
+	}
+
+	if r.Method != http.MethodPost {
+		return h.tokenError(w, errors.ErrInvalidRequest)
+	}
+
+	clientID, clientSecret, err := h.server.ClientInfoHandler(r)
+	if err != nil {
+		return h.tokenError(w, err)
+	}
+
+	cl, err := h.clientStorage.FindByID(r.Context(), clientID)
+	if err != nil {
+		h.tokenError(w, errors.ErrServerError)
+
+		return pkgErrors.Wrapf(err, "find client %q", clientID)
+	}
+
+	if cl == nil || cl.Secret != clientSecret {
+		return h.tokenError(w, errors.ErrInvalidClient)
+	}
+
+	tgr := &oauth2.TokenGenerateRequest{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Request:      r,
+	}
+
+	token := dynamo.NewToken()
+
+	if err := grant.ResolveTokenRequest(tgr, token); err != nil {
+		if _, ok := errors.Descriptions[err]; ok {
+			return h.tokenError(w, err)
+		}
+
+		h.tokenError(w, errors.ErrServerError)
+
+		return pkgErrors.Wrap(err, "resolve token request")
+	}
+
+	if resources != nil {
+		if tgr.Scope, err = resource.RestrictScope(tgr.Scope, resources); err != nil {
+			return h.tokenError(w, err)
+		}
+	}
+
+	if err := h.issueToken(r, cl, grant.TokenConfig(), tgr, token); err != nil {
+		h.tokenError(w, errors.ErrServerError)
+
+		return pkgErrors.Wrap(err, "issue token")
+	}
+
+	data := h.server.GetTokenData(token)
+	if extender, ok := grant.(responseExtender); ok {
+		extender.ExtendTokenResponse(data)
+	}
+
+	for key, value := range response {
+		data[key] = value
+	}
+
+	return writeJSON(w, data, nil, http.StatusOK)
+}
+
+// handleServerTokenRequest serves built-in grant the same way oauth2 server does, but adds parameters to the response.
+func (h *TokenHandler) handleServerTokenRequest(w http.ResponseWriter, r *http.Request, response map[string]interface{}) error {
+	gt, tgr, err := h.server.ValidationTokenRequest(r)
+	if err != nil {
+		return h.tokenError(w, err)
+	}
+
+	ti, err := h.server.GetAccessToken(r.Context(), gt, tgr)
+	if err != nil {
+		return h.tokenError(w, err)
+	}
+
+	data := h.server.GetTokenData(ti)
+	for key, value := range response {
+		data[key] = value
+	}
+
+	return writeJSON(w, data, nil, http.StatusOK)
+}
+
+// loadGrantToken returns authorization code or refresh token presented with the request.
+// Nil is returned for other grants and for codes and tokens, which do not exist, oauth2 server rejects them on its own.
+func (h *TokenHandler) loadGrantToken(r *http.Request) (*dynamo.Token, error) {
+	var ti oauth2.TokenInfo
+	var err error
+	switch oauth2.GrantType(r.FormValue("grant_type")) {
+	case oauth2.AuthorizationCode:
+		ti, err = h.tokenStore.GetByCode(r.Context(), r.FormValue("code"))
+	case oauth2.Refreshing:
+		ti, err = h.tokenStore.GetByRefresh(r.Context(), r.FormValue("refresh_token"))
+	}
+
+	if err != nil {
+		return nil, pkgErrors.Wrap(err, "find token")
+	}
+
+	token, _ := ti.(*dynamo.Token)
+
+	return token, nil
+}
+
+// resolveResources validates resource indicators and restricts requested scope to scopes accepted by the resources.
+// Authorization code and refresh token can be exchanged only for resources they were issued for.
+// Token store sets audience of every token created while serving returned request to requested resources.
+func (h *TokenHandler) resolveResources(r *http.Request, grantToken *dynamo.Token) (*http.Request, []*resource.Resource, error) {
+	grantType := oauth2.GrantType(r.FormValue("grant_type"))
+
+	// token exchange restricts audience on its own
+	if grantType == exchange.GrantType {
+		return r, nil, nil
+	}
+
+	requested := r.Form[resource.ParamResource]
+
+	if grantToken != nil {
+		authorized := resource.Audience(grantToken.Extension)
+
+		if len(requested) == 0 {
+			requested = authorized
+		} else if len(authorized) > 0 && !resource.IsSubset(requested, authorized) {
+			return nil, nil, resource.ErrInvalidTarget
+		}
+	}
+
+	if len(requested) == 0 {
+		return r, nil, nil
+	}
+
+	resources, err := h.resourceStorage.FindAll(r.Context(), requested)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch grantType {
+	case oauth2.AuthorizationCode:
+		if grantToken == nil {
+			return r, nil, nil
+		}
+
+		// oauth2 manager issues token with scope of the code, token store narrows it once the code is exchanged,
+		// so the code itself is never changed before client, redirect URI and code verifier are validated
+		scope, err := resource.RestrictScope(grantToken.GetScope(), resources)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		r = r.WithContext(dynamo.WithScope(r.Context(), scope))
+	case oauth2.Refreshing, oauth2.ClientCredentials:
+		scope := r.FormValue("scope")
+		if scope == "" && grantToken != nil {
+			scope = grantToken.GetScope()
+		}
+
+		// oauth2 manager takes refreshed token scope from the request as is, it must not exceed originally granted one
+		if grantToken != nil && !consent.BuildScopes(strings.Fields(grantToken.GetScope())).HasAll(consent.BuildScopes(strings.Fields(scope))) {
+			return nil, nil, errors.ErrInvalidScope
+		}
+
+		if scope, err = resource.RestrictScope(scope, resources); err != nil {
+			return nil, nil, err
+		}
+
+		r.Form.Set("scope", scope)
+	}
+
+	return r.WithContext(dynamo.WithExtension(r.Context(), resource.ClaimAudience, requested)), resources, nil
+}
+
+// resolveAuthorizationDetails returns authorization details token is issued for. Token exchanged for authorization code
+// carries details subject has approved, refreshed token keeps details it was issued with.
+func (h *TokenHandler) resolveAuthorizationDetails(r *http.Request, grantToken *dynamo.Token) (*http.Request, consent.AuthorizationDetails, error) {
+	if grantToken == nil {
+		return r, nil, nil
+	}
+
+	if grantToken.GetRefresh() != "" {
+		details, err := rar.Details(grantToken)
+
+		return r, details, err
+	}
+
+	details, err := h.rarHandler.ApprovedDetails(r.Context(), grantToken)
+	if err != nil || details == nil {
+		return r, details, err
+	}
+
+	return r.WithContext(dynamo.WithExtension(r.Context(), rar.ClaimAuthorizationDetails, details)), details, nil
+}
+
+// issueToken generates and stores token the same way oauth2 manager does for built-in grants.
+func (h *TokenHandler) issueToken(r *http.Request, cl *client.Client, cfg *manage.Config, tgr *oauth2.TokenGenerateRequest, ti *dynamo.Token) error {
+	createdAt := time.Now()
+
+	accessExp := cfg.AccessTokenExp
+	if tgr.AccessTokenExp > 0 {
+		accessExp = tgr.AccessTokenExp
+	}
+
+	ti.SetClientID(tgr.ClientID)
+	ti.SetUserID(tgr.UserID)
+	ti.SetScope(tgr.Scope)
+	ti.SetAccessCreateAt(createdAt)
+	ti.SetAccessExpiresIn(accessExp)
+
+	if cfg.IsGenerateRefresh {
+		ti.SetRefreshCreateAt(createdAt)
+		ti.SetRefreshExpiresIn(cfg.RefreshTokenExp)
+	}
+
+	access, refresh, err := h.accessGenerate.Token(r.Context(), &oauth2.GenerateBasic{
+		Client:    cl,
+		UserID:    tgr.UserID,
+		CreateAt:  createdAt,
+		TokenInfo: ti,
+		Request:   r,
+	}, cfg.IsGenerateRefresh)
+	if err != nil {
+		return pkgErrors.Wrap(err, "generate token")
+	}
+
+	ti.SetAccess(access)
+	ti.SetRefresh(refresh)
+
+	return pkgErrors.Wrap(h.tokenStore.Create(r.Context(), ti), "store token")
+}
+
+func (h *TokenHandler) tokenError(w http.ResponseWriter, err error) error {
+	data, statusCode, header := h.server.GetErrorData(err)
+
+	return writeJSON(w, data, header, statusCode)
+}
+
+// writeJSON writes response the same way oauth2 server writes token responses.
+func writeJSON(w http.ResponseWriter, data map[string]interface{}, header http.Header, statusCode int) error {
+	w.Header().Set("Content-Type", "application/json;charset=UTF-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
+
+	for key := range header {
+		w.Header().Set(key, header.Get(key))
+	}
+
+	w.WriteHeader(statusCode)
+
+	return json.NewEncoder(w).Encode(data)
+}
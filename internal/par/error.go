@@ -0,0 +1,17 @@
+package par
+
+import (
+	stdErrors "errors"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"net/http"
+)
+
+// ErrInvalidRequestURI is returned when request URI does not reference pushed request, which can still be used.
+var ErrInvalidRequestURI = stdErrors.New("invalid_request_uri")
+
+// register error with oauth2 server, so it is rendered as regular oauth2 error.
+func init() {
+	errors.Descriptions[ErrInvalidRequestURI] = "The request_uri in the authorization request is invalid, expired or already used"
+
+	errors.StatusCodes[ErrInvalidRequestURI] = http.StatusBadRequest
+}
@@ -0,0 +1,190 @@
+package par
+
+import (
+	"github.com/damejeras/auth/internal/app"
+	"github.com/damejeras/auth/internal/client"
+	"github.com/damejeras/auth/internal/integrity"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"github.com/go-oauth2/oauth2/v4/manage"
+	"github.com/go-oauth2/oauth2/v4/server"
+	pkgErrors "github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/segmentio/ksuid"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const paramRequestURI = "request_uri"
+
+// Handler serves pushed authorization request endpoint defined by RFC 9126
+// and resolves pushed requests referenced at authorization endpoint.
+type Handler struct {
+	repository    Repository
+	clientStorage *client.Storage
+	lifetime      time.Duration
+	logger        *zerolog.Logger
+}
+
+func NewHandler(repository Repository, clientStorage *client.Storage, cfg *app.Config, logger *zerolog.Logger) *Handler {
+	return &Handler{
+		repository:    repository,
+		clientStorage: clientStorage,
+		lifetime:      cfg.PushedAuthorizationConfig.Lifetime,
+		logger:        logger,
+	}
+}
+
+// HandlePushedAuthorizationRequest stores authorization parameters of authenticated client and responds with request URI.
+func (h *Handler) HandlePushedAuthorizationRequest(w http.ResponseWriter, r *http.Request) error {
+	if r.Method != http.MethodPost {
+		return app.WriteError(w, errors.ErrInvalidRequest)
+	}
+
+	if err := r.ParseForm(); err != nil {
+		return app.WriteError(w, errors.ErrInvalidRequest)
+	}
+
+	clientID, clientSecret, err := server.ClientBasicHandler(r)
+	if err != nil {
+		return app.WriteError(w, err)
+	}
+
+	cl, err := h.clientStorage.FindByID(r.Context(), clientID)
+	if err != nil {
+		app.WriteError(w, errors.ErrServerError)
+
+		return pkgErrors.Wrapf(err, "find client %q", clientID)
+	}
+
+	if cl == nil || cl.Secret != clientSecret {
+		return app.WriteError(w, errors.ErrInvalidClient)
+	}
+
+	parameters := make(url.Values, len(r.PostForm))
+	for key, values := range r.PostForm {
+		parameters[key] = append([]string(nil), values...)
+	}
+
+	// pushed request can not reference another request
+	if parameters.Get(paramRequestURI) != "" {
+		return app.WriteError(w, errors.ErrInvalidRequest)
+	}
+
+	if id := parameters.Get("client_id"); id != "" && id != cl.ID {
+		return app.WriteError(w, errors.ErrInvalidRequest)
+	}
+
+	if parameters.Get("response_type") == "" {
+		return app.WriteError(w, errors.ErrInvalidRequest)
+	}
+
+	// redirect URI is validated early, so invalid request never reaches user agent
+	if redirectURI := parameters.Get("redirect_uri"); redirectURI != "" {
+		if err := manage.DefaultValidateURI(cl.Domain, redirectURI); err != nil {
+			return app.WriteError(w, errors.ErrInvalidRequest)
+		}
+	}
+
+	parameters.Set("client_id", cl.ID)
+	parameters.Del("client_secret")
+	// verifiers are issued by login and consent providers only
+	parameters.Del("login_verifier")
+	parameters.Del("consent_verifier")
+
+	now := time.Now()
+
+	request := Request{
+		ID:         ksuid.New().String(),
+		ClientID:   cl.ID,
+		Parameters: parameters,
+		ExpiresAt:  now.Add(h.lifetime),
+		CreatedAt:  now,
+	}
+
+	if err := h.repository.Store(r.Context(), &request); err != nil {
+		app.WriteError(w, errors.ErrServerError)
+
+		return pkgErrors.Wrap(err, "store pushed request")
+	}
+
+	return app.WriteJSON(w, http.StatusCreated, map[string]interface{}{
+		paramRequestURI: request.RequestURI(),
+		"expires_in":    int64(h.lifetime / time.Second),
+	})
+}
+
+// AuthorizeMiddleware replaces request URI at authorization endpoint with pushed authorization parameters.
+// Footprints stay bound to the short URL, so parameters are never exposed to user agent.
+func (h *Handler) AuthorizeMiddleware(next func(w http.ResponseWriter, r *http.Request) error) func(w http.ResponseWriter, r *http.Request) error {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		query := r.URL.Query()
+
+		// request URIs not referencing pushed requests are resolved by request object middleware
+		requestURI := query.Get(paramRequestURI)
+		if !strings.HasPrefix(requestURI, RequestURIPrefix) {
+			cl, err := h.clientStorage.FindByID(r.Context(), query.Get("client_id"))
+			if err != nil {
+				app.WriteError(w, errors.ErrServerError)
+
+				return pkgErrors.Wrapf(err, "find client %q", query.Get("client_id"))
+			}
+
+			if cl != nil && cl.RequirePushedAuthorizationRequests {
+				return app.WriteError(w, errors.ErrInvalidRequest)
+			}
+
+			return next(w, r)
+		}
+
+		request, err := h.repository.FindByID(r.Context(), strings.TrimPrefix(requestURI, RequestURIPrefix))
+		if err != nil {
+			app.WriteError(w, errors.ErrServerError)
+
+			return pkgErrors.Wrap(err, "find pushed request")
+		}
+
+		if request == nil || request.ClientID != query.Get("client_id") {
+			return h.clientStorage.WriteAuthorizeError(w, r, ErrInvalidRequestURI)
+		}
+
+		// request URI starts single authorization, returns from login and consent providers are guarded by footprints
+		if !integrity.IsReturning(r) {
+			if request.Used || request.Expired() {
+				return h.clientStorage.WriteAuthorizeError(w, r, ErrInvalidRequestURI)
+			}
+
+			request.Used = true
+
+			// concurrent authorization requests may use the same request URI, only the first one marks it as used
+			err := h.repository.UpdateWithUsage(r.Context(), request)
+			switch {
+			case err == ErrRequestUsed:
+				return h.clientStorage.WriteAuthorizeError(w, r, ErrInvalidRequestURI)
+			case err != nil:
+				app.WriteError(w, errors.ErrServerError)
+
+				return pkgErrors.Wrap(err, "update pushed request with usage")
+			}
+		}
+
+		values := make(url.Values, len(request.Parameters))
+		for key := range request.Parameters {
+			values[key] = request.Parameters[key]
+		}
+
+		// only pushed parameters are used, user agent can not alter them with query parameters
+		for _, key := range append([]string{"client_id", paramRequestURI}, integrity.ReturnParams()...) {
+			if value, ok := query[key]; ok {
+				values[key] = value
+			}
+		}
+
+		resolved := integrity.WithRequestURL(r.Clone(r.Context()), integrity.RequestURL(r))
+		resolved.URL.RawQuery = values.Encode()
+		resolved.Form = nil
+
+		return next(w, resolved)
+	}
+}
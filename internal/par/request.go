@@ -0,0 +1,42 @@
+package par
+
+import (
+	"context"
+	stdErrors "errors"
+	"net/url"
+	"time"
+)
+
+// RequestURIPrefix is prefix of request URIs referencing pushed authorization requests.
+const RequestURIPrefix = "urn:ietf:params:oauth:request_uri:"
+
+// ErrRequestUsed is returned by repository, when pushed request has already been used.
+var ErrRequestUsed = stdErrors.New("pushed request is already used")
+
+// Request is authorization request pushed by client before redirecting user agent to authorization endpoint.
+type Request struct {
+	ID         string
+	ClientID   string
+	Parameters url.Values
+	Used       bool
+	ExpiresAt  time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (r *Request) Expired() bool {
+	return time.Now().After(r.ExpiresAt)
+}
+
+// RequestURI returns reference, which client passes to authorization endpoint instead of authorization parameters.
+func (r *Request) RequestURI() string {
+	return RequestURIPrefix + r.ID
+}
+
+type Repository interface {
+	Store(context.Context, *Request) error
+	// UpdateWithUsage marks request as used, it returns ErrRequestUsed if request has already been used.
+	UpdateWithUsage(context.Context, *Request) error
+	FindByID(context.Context, string) (*Request, error)
+}
@@ -0,0 +1,200 @@
+package persistence
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/damejeras/auth/internal/ciba"
+	"github.com/pkg/errors"
+	"strconv"
+	"time"
+)
+
+const tableBackchannelRequest = "oauth2_backchannel_request"
+
+type backchannelRequestRepresentation struct {
+	ID                string
+	ClientID          string
+	Scope             string
+	LoginHint         string
+	BindingMessage    string
+	NotificationToken string
+	SubjectID         string
+	Denied            bool
+	Interval          int64
+	PolledAt          int64
+	ExpiresAt         int64
+	CreatedAt         int64
+	UpdatedAt         int64
+}
+
+func (r backchannelRequestRepresentation) toRequest() *ciba.Request {
+	return &ciba.Request{
+		ID:                r.ID,
+		ClientID:          r.ClientID,
+		Scope:             r.Scope,
+		LoginHint:         r.LoginHint,
+		BindingMessage:    r.BindingMessage,
+		NotificationToken: r.NotificationToken,
+		SubjectID:         r.SubjectID,
+		Denied:            r.Denied,
+		Interval:          time.Duration(r.Interval) * time.Second,
+		PolledAt:          time.Unix(r.PolledAt, 0),
+		ExpiresAt:         time.Unix(r.ExpiresAt, 0),
+		CreatedAt:         time.Unix(r.CreatedAt, 0),
+		UpdatedAt:         time.Unix(r.UpdatedAt, 0),
+	}
+}
+
+type backchannelRequestRepository struct {
+	db *dynamodb.DynamoDB
+}
+
+func NewBackchannelRequestRepository(db *dynamodb.DynamoDB) (ciba.Repository, error) {
+	if err := migrateBackchannelRequestTable(db); err != nil {
+		return nil, errors.Wrap(err, "run table migration")
+	}
+
+	return &backchannelRequestRepository{db: db}, nil
+}
+
+func (b *backchannelRequestRepository) Store(ctx context.Context, request *ciba.Request) error {
+	_, err := b.db.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(tableBackchannelRequest),
+		Item: map[string]*dynamodb.AttributeValue{
+			"ID":                {S: aws.String(request.ID)},
+			"ClientID":          {S: aws.String(request.ClientID)},
+			"Scope":             {S: aws.String(request.Scope)},
+			"LoginHint":         {S: aws.String(request.LoginHint)},
+			"BindingMessage":    {S: aws.String(request.BindingMessage)},
+			"NotificationToken": {S: aws.String(request.NotificationToken)},
+			"SubjectID":         {S: aws.String(request.SubjectID)},
+			"Denied":            {BOOL: aws.Bool(request.Denied)},
+			"Interval":          {N: aws.String(strconv.Itoa(int(request.Interval / time.Second)))},
+			"PolledAt":          {N: aws.String(strconv.Itoa(int(request.PolledAt.Unix())))},
+			"ExpiresAt":         {N: aws.String(strconv.Itoa(int(request.ExpiresAt.Unix())))},
+			"CreatedAt":         {N: aws.String(strconv.Itoa(int(time.Now().Unix())))},
+			"UpdatedAt":         {N: aws.String(strconv.Itoa(0))},
+		},
+	})
+
+	return errors.Wrap(err, "execute query")
+}
+
+func (b *backchannelRequestRepository) UpdateWithApproval(ctx context.Context, request *ciba.Request) error {
+	_, err := b.db.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableBackchannelRequest),
+		Key: map[string]*dynamodb.AttributeValue{
+			"ID": {S: aws.String(request.ID)},
+		},
+		UpdateExpression: aws.String("SET SubjectID = :SubjectID, UpdatedAt = :UpdatedAt"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":SubjectID": {S: aws.String(request.SubjectID)},
+			":UpdatedAt": {N: aws.String(strconv.Itoa(int(time.Now().Unix())))},
+		},
+	})
+
+	return errors.Wrap(err, "execute query")
+}
+
+func (b *backchannelRequestRepository) UpdateWithDenial(ctx context.Context, request *ciba.Request) error {
+	_, err := b.db.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableBackchannelRequest),
+		Key: map[string]*dynamodb.AttributeValue{
+			"ID": {S: aws.String(request.ID)},
+		},
+		UpdateExpression: aws.String("SET Denied = :Denied, UpdatedAt = :UpdatedAt"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":Denied":    {BOOL: aws.Bool(request.Denied)},
+			":UpdatedAt": {N: aws.String(strconv.Itoa(int(time.Now().Unix())))},
+		},
+	})
+
+	return errors.Wrap(err, "execute query")
+}
+
+func (b *backchannelRequestRepository) UpdateWithPoll(ctx context.Context, request *ciba.Request) error {
+	_, err := b.db.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableBackchannelRequest),
+		Key: map[string]*dynamodb.AttributeValue{
+			"ID": {S: aws.String(request.ID)},
+		},
+		UpdateExpression: aws.String("SET PolledAt = :PolledAt, #Interval = :Interval, UpdatedAt = :UpdatedAt"),
+		ExpressionAttributeNames: map[string]*string{
+			// INTERVAL is reserved word
+			"#Interval": aws.String("Interval"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":PolledAt":  {N: aws.String(strconv.Itoa(int(request.PolledAt.Unix())))},
+			":Interval":  {N: aws.String(strconv.Itoa(int(request.Interval / time.Second)))},
+			":UpdatedAt": {N: aws.String(strconv.Itoa(int(time.Now().Unix())))},
+		},
+	})
+
+	return errors.Wrap(err, "execute query")
+}
+
+func (b *backchannelRequestRepository) FindByID(ctx context.Context, id string) (*ciba.Request, error) {
+	result, err := b.db.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(tableBackchannelRequest),
+		Key: map[string]*dynamodb.AttributeValue{
+			"ID": {S: aws.String(id)},
+		},
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "execute query")
+	}
+
+	if len(result.Item) == 0 {
+		return nil, nil
+	}
+
+	var representation backchannelRequestRepresentation
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &representation); err != nil {
+		return nil, errors.Wrap(err, "unmarshal query result")
+	}
+
+	return representation.toRequest(), nil
+}
+
+func (b *backchannelRequestRepository) Delete(ctx context.Context, request *ciba.Request) error {
+	_, err := b.db.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(tableBackchannelRequest),
+		Key: map[string]*dynamodb.AttributeValue{
+			"ID": {S: aws.String(request.ID)},
+		},
+	})
+
+	return errors.Wrap(err, "execute query")
+}
+
+func migrateBackchannelRequestTable(db *dynamodb.DynamoDB) error {
+	tables, err := db.ListTables(nil)
+	if err != nil {
+		return err
+	}
+
+	for _, table := range tables.TableNames {
+		if *table == tableBackchannelRequest {
+			return nil
+		}
+	}
+
+	_, err = db.CreateTable(&dynamodb.CreateTableInput{
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{AttributeName: aws.String("ID"), AttributeType: aws.String("S")},
+		},
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("ID"), KeyType: aws.String("HASH")},
+		},
+		ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(5),
+			WriteCapacityUnits: aws.Int64(10),
+		},
+		TableName: aws.String(tableBackchannelRequest),
+	})
+
+	return err
+}
@@ -15,12 +15,51 @@ import (
 const tableConsent = "oauth2_consent"
 
 type consentRepresentation struct {
-	ID        string
-	ClientID  string
-	SubjectID string
-	Scopes    []byte
-	CreatedAt int64
-	UpdatedAt int64
+	ID                   string
+	ClientID             string
+	SubjectID            string
+	Scopes               []byte
+	AuthorizationDetails []byte
+	ExpiresAt            int64
+	// OnceAuthorizationDetails are absent on items stored before one-off grants were tracked.
+	OnceAuthorizationDetails []byte
+	CreatedAt                int64
+	UpdatedAt                int64
+}
+
+func (r consentRepresentation) toConsent() (*consent.Consent, error) {
+	var scopes consent.Scopes
+	if err := json.Unmarshal(r.Scopes, &scopes); err != nil {
+		return nil, errors.Wrap(err, "unmarshal scopes")
+	}
+
+	var details consent.AuthorizationDetails
+	if err := unmarshalOptional(r.AuthorizationDetails, &details); err != nil {
+		return nil, errors.Wrap(err, "unmarshal authorization details")
+	}
+
+	var onceDetails consent.AuthorizationDetails
+	if err := unmarshalOptional(r.OnceAuthorizationDetails, &onceDetails); err != nil {
+		return nil, errors.Wrap(err, "unmarshal once authorization details")
+	}
+
+	cs := consent.Consent{
+		ID:                       r.ID,
+		ClientID:                 r.ClientID,
+		SubjectID:                r.SubjectID,
+		Scopes:                   scopes,
+		AuthorizationDetails:     details,
+		OnceAuthorizationDetails: onceDetails,
+		CreatedAt:                time.Unix(r.CreatedAt, 0),
+		UpdatedAt:                time.Unix(r.UpdatedAt, 0),
+	}
+
+	// items stored before consent expiry was introduced do not have the attribute
+	if r.ExpiresAt != 0 {
+		cs.ExpiresAt = time.Unix(r.ExpiresAt, 0)
+	}
+
+	return &cs, nil
 }
 
 type consentRepository struct {
@@ -41,36 +80,63 @@ func (c *consentRepository) Store(ctx context.Context, consent *consent.Consent)
 		return errors.Wrap(err, "marshal scopes")
 	}
 
+	detailsBytes, err := json.Marshal(consent.AuthorizationDetails)
+	if err != nil {
+		return errors.Wrap(err, "marshal authorization details")
+	}
+
+	onceDetailsBytes, err := json.Marshal(consent.OnceAuthorizationDetails)
+	if err != nil {
+		return errors.Wrap(err, "marshal once authorization details")
+	}
+
 	_, err = c.db.PutItemWithContext(ctx, &dynamodb.PutItemInput{
 		TableName: aws.String(tableConsent),
 		Item: map[string]*dynamodb.AttributeValue{
-			"ID":        {S: aws.String(consent.ID)},
-			"ClientID":  {S: aws.String(consent.ClientID)},
-			"SubjectID": {S: aws.String(consent.SubjectID)},
-			"Scopes":    {B: scopeBytes},
-			"CreatedAt": {N: aws.String(strconv.Itoa(int(time.Now().Unix())))},
-			"UpdatedAt": {N: aws.String(strconv.Itoa(0))},
+			"ID":                       {S: aws.String(consent.ID)},
+			"ClientID":                 {S: aws.String(consent.ClientID)},
+			"SubjectID":                {S: aws.String(consent.SubjectID)},
+			"Scopes":                   {B: scopeBytes},
+			"AuthorizationDetails":     {B: detailsBytes},
+			"OnceAuthorizationDetails": {B: onceDetailsBytes},
+			"ExpiresAt":                {N: aws.String(strconv.Itoa(int(optionalUnix(consent.ExpiresAt))))},
+			"CreatedAt":                {N: aws.String(strconv.Itoa(int(time.Now().Unix())))},
+			"UpdatedAt":                {N: aws.String(strconv.Itoa(0))},
 		},
 	})
 
 	return errors.Wrap(err, "execute query")
 }
 
-func (c *consentRepository) UpdateWithScopes(ctx context.Context, consent *consent.Consent) error {
+func (c *consentRepository) UpdateWithGrant(ctx context.Context, consent *consent.Consent) error {
 	scopeBytes, err := json.Marshal(consent.Scopes)
 	if err != nil {
 		return errors.Wrap(err, "marshal scopes")
 	}
 
+	detailsBytes, err := json.Marshal(consent.AuthorizationDetails)
+	if err != nil {
+		return errors.Wrap(err, "marshal authorization details")
+	}
+
+	onceDetailsBytes, err := json.Marshal(consent.OnceAuthorizationDetails)
+	if err != nil {
+		return errors.Wrap(err, "marshal once authorization details")
+	}
+
 	_, err = c.db.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
 		TableName: aws.String(tableConsent),
 		Key: map[string]*dynamodb.AttributeValue{
 			"ID": {S: aws.String(consent.ID)},
 		},
-		UpdateExpression: aws.String("SET Scopes = :Scopes, UpdatedAt = :UpdatedAt"),
+		UpdateExpression: aws.String("SET Scopes = :Scopes, AuthorizationDetails = :AuthorizationDetails, " +
+			"OnceAuthorizationDetails = :OnceAuthorizationDetails, ExpiresAt = :ExpiresAt, UpdatedAt = :UpdatedAt"),
 		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
-			":Scopes":    {B: scopeBytes},
-			":UpdatedAt": {N: aws.String(strconv.Itoa(int(time.Now().Unix())))},
+			":Scopes":                   {B: scopeBytes},
+			":AuthorizationDetails":     {B: detailsBytes},
+			":OnceAuthorizationDetails": {B: onceDetailsBytes},
+			":ExpiresAt":                {N: aws.String(strconv.Itoa(int(optionalUnix(consent.ExpiresAt))))},
+			":UpdatedAt":                {N: aws.String(strconv.Itoa(int(time.Now().Unix())))},
 		},
 	})
 
@@ -115,22 +181,82 @@ func (c *consentRepository) FindByClientAndSubject(ctx context.Context, clientID
 		return nil, err
 	}
 
-	var scopes consent.Scopes
-	if err := json.Unmarshal(representation.Scopes, &scopes); err != nil {
-		return nil, err
+	return representation.toConsent()
+}
+
+func (c *consentRepository) FindBySubject(ctx context.Context, subjectID string) ([]*consent.Consent, error) {
+	input := &dynamodb.QueryInput{
+		IndexName: aws.String("ConsentSubjectIndex"),
+		KeyConditions: map[string]*dynamodb.Condition{
+			"SubjectID": {
+				ComparisonOperator: aws.String("EQ"),
+				AttributeValueList: []*dynamodb.AttributeValue{
+					{
+						S: aws.String(subjectID),
+					},
+				},
+			},
+		},
+		TableName: aws.String(tableConsent),
+	}
+
+	var representations []consentRepresentation
+	var unmarshalErr error
+	err := c.db.QueryPagesWithContext(ctx, input, func(output *dynamodb.QueryOutput, lastPage bool) bool {
+		var page []consentRepresentation
+		if unmarshalErr = dynamodbattribute.UnmarshalListOfMaps(output.Items, &page); unmarshalErr != nil {
+			return false
+		}
+
+		representations = append(representations, page...)
+
+		return true
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "execute query")
+	}
+
+	if unmarshalErr != nil {
+		return nil, errors.Wrap(unmarshalErr, "unmarshal query result")
+	}
+
+	consents := make([]*consent.Consent, len(representations))
+	for i := range representations {
+		if consents[i], err = representations[i].toConsent(); err != nil {
+			return nil, errors.Wrapf(err, "convert consent %q", representations[i].ID)
+		}
 	}
 
-	return &consent.Consent{
-		ID:        representation.ID,
-		ClientID:  representation.ClientID,
-		SubjectID: representation.SubjectID,
-		Scopes:    scopes,
-		CreatedAt: time.Unix(representation.CreatedAt, 0),
-		UpdatedAt: time.Unix(representation.UpdatedAt, 0),
-	}, nil
+	return consents, nil
+}
+
+func (c *consentRepository) Delete(ctx context.Context, consent *consent.Consent) error {
+	_, err := c.db.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(tableConsent),
+		Key: map[string]*dynamodb.AttributeValue{
+			"ID": {S: aws.String(consent.ID)},
+		},
+	})
+
+	return errors.Wrap(err, "execute query")
 }
 
 func migrateIdentityConsentTable(db *dynamodb.DynamoDB) error {
+	subjectIndex := &dynamodb.GlobalSecondaryIndex{
+		IndexName: aws.String("ConsentSubjectIndex"),
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("SubjectID"), KeyType: aws.String("HASH")},
+		},
+		Projection: &dynamodb.Projection{
+			ProjectionType: aws.String("ALL"),
+		},
+		ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(5),
+			WriteCapacityUnits: aws.Int64(10),
+		},
+	}
+
 	tables, err := db.ListTables(nil)
 	if err != nil {
 		return err
@@ -138,7 +264,9 @@ func migrateIdentityConsentTable(db *dynamodb.DynamoDB) error {
 
 	for _, table := range tables.TableNames {
 		if *table == tableConsent {
-			return nil
+			return migrateGlobalSecondaryIndex(db, tableConsent, subjectIndex, &dynamodb.AttributeDefinition{
+				AttributeName: aws.String("SubjectID"), AttributeType: aws.String("S"),
+			})
 		}
 	}
 
@@ -166,6 +294,7 @@ func migrateIdentityConsentTable(db *dynamodb.DynamoDB) error {
 					WriteCapacityUnits: aws.Int64(10),
 				},
 			},
+			subjectIndex,
 		},
 		ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
 			ReadCapacityUnits:  aws.Int64(5),
@@ -16,17 +16,77 @@ import (
 const tableConsentChallenge = "oauth2_consent_challenge"
 
 type consentChallengeRepresentation struct {
-	ID              string
-	Verifier        string
-	ClientID        string
-	SubjectID       string
-	RequestedScopes []byte
-	MissingScopes   []byte
-	GrantedScopes   []byte
-	Footprint       []byte
-	Used            bool
-	CreatedAt       int
-	UpdatedAt       int
+	ID                            string
+	Verifier                      string
+	ClientID                      string
+	SubjectID                     string
+	RequestedScopes               []byte
+	MissingScopes                 []byte
+	GrantedScopes                 []byte
+	RequestedAuthorizationDetails []byte
+	MissingAuthorizationDetails   []byte
+	GrantedAuthorizationDetails   []byte
+	Rejection                     []byte
+	Footprint                     []byte
+	Used                          bool
+	CreatedAt                     int
+	UpdatedAt                     int
+}
+
+func (r consentChallengeRepresentation) toChallenge() (*consent.Challenge, error) {
+	var requestedScopes, missingScopes, grantedScopes consent.Scopes
+	if err := json.Unmarshal(r.RequestedScopes, &requestedScopes); err != nil {
+		return nil, errors.Wrap(err, "unmarshal requested scopes")
+	}
+
+	if err := json.Unmarshal(r.MissingScopes, &missingScopes); err != nil {
+		return nil, errors.Wrap(err, "unmarshal missing scopes")
+	}
+
+	if err := json.Unmarshal(r.GrantedScopes, &grantedScopes); err != nil {
+		return nil, errors.Wrap(err, "unmarshal granted scopes")
+	}
+
+	var requestedDetails, missingDetails, grantedDetails consent.AuthorizationDetails
+	if err := unmarshalOptional(r.RequestedAuthorizationDetails, &requestedDetails); err != nil {
+		return nil, errors.Wrap(err, "unmarshal requested authorization details")
+	}
+
+	if err := unmarshalOptional(r.MissingAuthorizationDetails, &missingDetails); err != nil {
+		return nil, errors.Wrap(err, "unmarshal missing authorization details")
+	}
+
+	if err := unmarshalOptional(r.GrantedAuthorizationDetails, &grantedDetails); err != nil {
+		return nil, errors.Wrap(err, "unmarshal granted authorization details")
+	}
+
+	var rejection *consent.Rejection
+	if err := json.Unmarshal(r.Rejection, &rejection); err != nil {
+		return nil, errors.Wrap(err, "unmarshal rejection")
+	}
+
+	var footprint integrity.Footprint
+	if err := json.Unmarshal(r.Footprint, &footprint); err != nil {
+		return nil, errors.Wrap(err, "unmarshal footprint")
+	}
+
+	return &consent.Challenge{
+		ID:                            r.ID,
+		Verifier:                      r.Verifier,
+		ClientID:                      r.ClientID,
+		SubjectID:                     r.SubjectID,
+		RequestedScopes:               requestedScopes,
+		MissingScopes:                 missingScopes,
+		GrantedScopes:                 grantedScopes,
+		RequestedAuthorizationDetails: requestedDetails,
+		MissingAuthorizationDetails:   missingDetails,
+		GrantedAuthorizationDetails:   grantedDetails,
+		Rejection:                     rejection,
+		Footprint:                     &footprint,
+		Used:                          r.Used,
+		CreatedAt:                     time.Unix(int64(r.CreatedAt), 0),
+		UpdatedAt:                     time.Unix(int64(r.UpdatedAt), 0),
+	}, nil
 }
 
 type consentChallengeRepository struct {
@@ -57,6 +117,26 @@ func (c *consentChallengeRepository) Store(ctx context.Context, challenge *conse
 		return errors.Wrap(err, "marshal granted scopes")
 	}
 
+	requestedDetails, err := json.Marshal(challenge.RequestedAuthorizationDetails)
+	if err != nil {
+		return errors.Wrap(err, "marshal requested authorization details")
+	}
+
+	missingDetails, err := json.Marshal(challenge.MissingAuthorizationDetails)
+	if err != nil {
+		return errors.Wrap(err, "marshal missing authorization details")
+	}
+
+	grantedDetails, err := json.Marshal(challenge.GrantedAuthorizationDetails)
+	if err != nil {
+		return errors.Wrap(err, "marshal granted authorization details")
+	}
+
+	rejection, err := json.Marshal(challenge.Rejection)
+	if err != nil {
+		return errors.Wrap(err, "marshal rejection")
+	}
+
 	footprint, err := json.Marshal(challenge.Footprint)
 	if err != nil {
 		return errors.Wrap(err, "marshal footprint")
@@ -65,27 +145,36 @@ func (c *consentChallengeRepository) Store(ctx context.Context, challenge *conse
 	_, err = c.db.PutItemWithContext(ctx, &dynamodb.PutItemInput{
 		TableName: aws.String(tableConsentChallenge),
 		Item: map[string]*dynamodb.AttributeValue{
-			"ID":              {S: aws.String(challenge.ID)},
-			"Verifier":        {S: aws.String(challenge.Verifier)},
-			"ClientID":        {S: aws.String(challenge.ClientID)},
-			"SubjectID":       {S: aws.String(challenge.SubjectID)},
-			"RequestedScopes": {B: requestedScopes},
-			"MissingScopes":   {B: missingScopes},
-			"GrantedScopes":   {B: grantedScopes},
-			"Footprint":       {B: footprint},
-			"Used":            {BOOL: aws.Bool(challenge.Used)},
-			"CreatedAt":       {N: aws.String(strconv.Itoa(int(time.Now().Unix())))},
-			"UpdatedAt":       {N: aws.String(strconv.Itoa(0))},
+			"ID":                            {S: aws.String(challenge.ID)},
+			"Verifier":                      {S: aws.String(challenge.Verifier)},
+			"ClientID":                      {S: aws.String(challenge.ClientID)},
+			"SubjectID":                     {S: aws.String(challenge.SubjectID)},
+			"RequestedScopes":               {B: requestedScopes},
+			"MissingScopes":                 {B: missingScopes},
+			"GrantedScopes":                 {B: grantedScopes},
+			"RequestedAuthorizationDetails": {B: requestedDetails},
+			"MissingAuthorizationDetails":   {B: missingDetails},
+			"GrantedAuthorizationDetails":   {B: grantedDetails},
+			"Rejection":                     {B: rejection},
+			"Footprint":                     {B: footprint},
+			"Used":                          {BOOL: aws.Bool(challenge.Used)},
+			"CreatedAt":                     {N: aws.String(strconv.Itoa(int(time.Now().Unix())))},
+			"UpdatedAt":                     {N: aws.String(strconv.Itoa(0))},
 		},
 	})
 
 	return errors.Wrap(err, "execute query")
 }
 
-func (c *consentChallengeRepository) UpdateWithGrantedScopes(ctx context.Context, challenge *consent.Challenge) error {
+func (c *consentChallengeRepository) UpdateWithGrant(ctx context.Context, challenge *consent.Challenge) error {
 	grantedScopes, err := json.Marshal(challenge.GrantedScopes)
 	if err != nil {
-		return errors.Wrap(err, "marshal authorization")
+		return errors.Wrap(err, "marshal granted scopes")
+	}
+
+	grantedDetails, err := json.Marshal(challenge.GrantedAuthorizationDetails)
+	if err != nil {
+		return errors.Wrap(err, "marshal granted authorization details")
 	}
 
 	_, err = c.db.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
@@ -93,10 +182,32 @@ func (c *consentChallengeRepository) UpdateWithGrantedScopes(ctx context.Context
 		Key: map[string]*dynamodb.AttributeValue{
 			"ID": {S: aws.String(challenge.ID)},
 		},
-		UpdateExpression: aws.String("SET GrantedScopes = :GrantedScopes, UpdatedAt = :UpdatedAt"),
+		UpdateExpression: aws.String("SET GrantedScopes = :GrantedScopes, GrantedAuthorizationDetails = :GrantedAuthorizationDetails, UpdatedAt = :UpdatedAt"),
 		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
-			":GrantedScopes": {B: grantedScopes},
-			":UpdatedAt":     {N: aws.String(strconv.Itoa(int(time.Now().Unix())))},
+			":GrantedScopes":               {B: grantedScopes},
+			":GrantedAuthorizationDetails": {B: grantedDetails},
+			":UpdatedAt":                   {N: aws.String(strconv.Itoa(int(time.Now().Unix())))},
+		},
+	})
+
+	return errors.Wrap(err, "execute query")
+}
+
+func (c *consentChallengeRepository) UpdateWithRejection(ctx context.Context, challenge *consent.Challenge) error {
+	rejection, err := json.Marshal(challenge.Rejection)
+	if err != nil {
+		return errors.Wrap(err, "marshal rejection")
+	}
+
+	_, err = c.db.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableConsentChallenge),
+		Key: map[string]*dynamodb.AttributeValue{
+			"ID": {S: aws.String(challenge.ID)},
+		},
+		UpdateExpression: aws.String("SET Rejection = :Rejection, UpdatedAt = :UpdatedAt"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":Rejection": {B: rejection},
+			":UpdatedAt": {N: aws.String(strconv.Itoa(int(time.Now().Unix())))},
 		},
 	})
 
@@ -124,37 +235,7 @@ func (c *consentChallengeRepository) FindByID(ctx context.Context, id string) (*
 		return nil, errors.Wrap(err, "unmarshal query result")
 	}
 
-	var requestedScopes, missingScopes, grantedScopes consent.Scopes
-	if err := json.Unmarshal(representation.RequestedScopes, &requestedScopes); err != nil {
-		return nil, errors.Wrap(err, "unmarshal requested scopes")
-	}
-
-	if err := json.Unmarshal(representation.MissingScopes, &missingScopes); err != nil {
-		return nil, errors.Wrap(err, "unmarshal missing scopes")
-	}
-
-	if err := json.Unmarshal(representation.GrantedScopes, &grantedScopes); err != nil {
-		return nil, errors.Wrap(err, "unmarshal granted scopes")
-	}
-
-	var footprint integrity.Footprint
-	if err := json.Unmarshal(representation.Footprint, &footprint); err != nil {
-		return nil, errors.Wrap(err, "unmarshal footprint")
-	}
-
-	return &consent.Challenge{
-		ID:              representation.ID,
-		Verifier:        representation.Verifier,
-		ClientID:        representation.ClientID,
-		SubjectID:       representation.SubjectID,
-		RequestedScopes: requestedScopes,
-		MissingScopes:   missingScopes,
-		GrantedScopes:   grantedScopes,
-		Footprint:       &footprint,
-		Used:            representation.Used,
-		CreatedAt:       time.Unix(int64(representation.CreatedAt), 0),
-		UpdatedAt:       time.Unix(int64(representation.UpdatedAt), 0),
-	}, nil
+	return representation.toChallenge()
 }
 
 func (c *consentChallengeRepository) FindByVerifier(ctx context.Context, verifier string) (*consent.Challenge, error) {
@@ -191,37 +272,7 @@ func (c *consentChallengeRepository) FindByVerifier(ctx context.Context, verifie
 		return nil, errors.Wrap(err, "unmarshal query result")
 	}
 
-	var requestedScopes, missingScopes, grantedScopes consent.Scopes
-	if err := json.Unmarshal(representation.RequestedScopes, &requestedScopes); err != nil {
-		return nil, errors.Wrap(err, "unmarshal requested scopes")
-	}
-
-	if err := json.Unmarshal(representation.MissingScopes, &missingScopes); err != nil {
-		return nil, errors.Wrap(err, "unmarshal missing scopes")
-	}
-
-	if err := json.Unmarshal(representation.GrantedScopes, &grantedScopes); err != nil {
-		return nil, errors.Wrap(err, "unmarshal granted scopes")
-	}
-
-	var footprint integrity.Footprint
-	if err := json.Unmarshal(representation.Footprint, &footprint); err != nil {
-		return nil, errors.Wrap(err, "unmarshal footprint")
-	}
-
-	return &consent.Challenge{
-		ID:              representation.ID,
-		Verifier:        representation.Verifier,
-		ClientID:        representation.ClientID,
-		SubjectID:       representation.SubjectID,
-		RequestedScopes: requestedScopes,
-		MissingScopes:   missingScopes,
-		GrantedScopes:   grantedScopes,
-		Footprint:       &footprint,
-		Used:            representation.Used,
-		CreatedAt:       time.Unix(int64(representation.CreatedAt), 0),
-		UpdatedAt:       time.Unix(int64(representation.UpdatedAt), 0),
-	}, nil
+	return representation.toChallenge()
 }
 
 func (c *consentChallengeRepository) Delete(ctx context.Context, challenge *consent.Challenge) error {
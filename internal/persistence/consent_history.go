@@ -0,0 +1,215 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/damejeras/auth/internal/consent"
+	"github.com/pkg/errors"
+	"strconv"
+	"time"
+)
+
+const tableConsentHistory = "oauth2_consent_history"
+
+type consentEventRepresentation struct {
+	ID                          string
+	Type                        string
+	ClientID                    string
+	SubjectID                   string
+	AddedScopes                 []byte
+	RemovedScopes               []byte
+	AddedAuthorizationDetails   []byte
+	RemovedAuthorizationDetails []byte
+	ExpiresAt                   int64
+	ChallengeID                 string
+	RequestID                   string
+	CreatedAt                   int64
+}
+
+func (r consentEventRepresentation) toEvent() (*consent.Event, error) {
+	event := consent.Event{
+		ID:          r.ID,
+		Type:        r.Type,
+		ClientID:    r.ClientID,
+		SubjectID:   r.SubjectID,
+		ChallengeID: r.ChallengeID,
+		RequestID:   r.RequestID,
+		CreatedAt:   time.Unix(r.CreatedAt, 0),
+	}
+
+	if r.ExpiresAt != 0 {
+		event.ExpiresAt = time.Unix(r.ExpiresAt, 0)
+	}
+
+	if err := json.Unmarshal(r.AddedScopes, &event.AddedScopes); err != nil {
+		return nil, errors.Wrap(err, "unmarshal added scopes")
+	}
+
+	if err := json.Unmarshal(r.RemovedScopes, &event.RemovedScopes); err != nil {
+		return nil, errors.Wrap(err, "unmarshal removed scopes")
+	}
+
+	if err := json.Unmarshal(r.AddedAuthorizationDetails, &event.AddedAuthorizationDetails); err != nil {
+		return nil, errors.Wrap(err, "unmarshal added authorization details")
+	}
+
+	if err := json.Unmarshal(r.RemovedAuthorizationDetails, &event.RemovedAuthorizationDetails); err != nil {
+		return nil, errors.Wrap(err, "unmarshal removed authorization details")
+	}
+
+	return &event, nil
+}
+
+type consentHistoryRepository struct {
+	db *dynamodb.DynamoDB
+}
+
+func NewConsentHistoryRepository(db *dynamodb.DynamoDB) (consent.HistoryRepository, error) {
+	if err := migrateConsentHistoryTable(db); err != nil {
+		return nil, errors.Wrap(err, "run table migration")
+	}
+
+	return &consentHistoryRepository{db: db}, nil
+}
+
+func (c *consentHistoryRepository) Store(ctx context.Context, event *consent.Event) error {
+	addedScopesBytes, err := json.Marshal(event.AddedScopes)
+	if err != nil {
+		return errors.Wrap(err, "marshal added scopes")
+	}
+
+	removedScopesBytes, err := json.Marshal(event.RemovedScopes)
+	if err != nil {
+		return errors.Wrap(err, "marshal removed scopes")
+	}
+
+	addedDetailsBytes, err := json.Marshal(event.AddedAuthorizationDetails)
+	if err != nil {
+		return errors.Wrap(err, "marshal added authorization details")
+	}
+
+	removedDetailsBytes, err := json.Marshal(event.RemovedAuthorizationDetails)
+	if err != nil {
+		return errors.Wrap(err, "marshal removed authorization details")
+	}
+
+	_, err = c.db.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(tableConsentHistory),
+		Item: map[string]*dynamodb.AttributeValue{
+			"ID":                          {S: aws.String(event.ID)},
+			"Type":                        {S: aws.String(event.Type)},
+			"ClientID":                    {S: aws.String(event.ClientID)},
+			"SubjectID":                   {S: aws.String(event.SubjectID)},
+			"AddedScopes":                 {B: addedScopesBytes},
+			"RemovedScopes":               {B: removedScopesBytes},
+			"AddedAuthorizationDetails":   {B: addedDetailsBytes},
+			"RemovedAuthorizationDetails": {B: removedDetailsBytes},
+			"ExpiresAt":                   {N: aws.String(strconv.Itoa(int(optionalUnix(event.ExpiresAt))))},
+			"ChallengeID":                 {S: aws.String(event.ChallengeID)},
+			"RequestID":                   {S: aws.String(event.RequestID)},
+			"CreatedAt":                   {N: aws.String(strconv.Itoa(int(event.CreatedAt.Unix())))},
+		},
+		// history is append-only
+		ConditionExpression: aws.String("attribute_not_exists(ID)"),
+	})
+
+	return errors.Wrap(err, "execute query")
+}
+
+func (c *consentHistoryRepository) FindBySubject(ctx context.Context, subjectID string) ([]*consent.Event, error) {
+	input := &dynamodb.QueryInput{
+		IndexName: aws.String("ConsentHistorySubjectIndex"),
+		KeyConditions: map[string]*dynamodb.Condition{
+			"SubjectID": {
+				ComparisonOperator: aws.String("EQ"),
+				AttributeValueList: []*dynamodb.AttributeValue{
+					{
+						S: aws.String(subjectID),
+					},
+				},
+			},
+		},
+		// event IDs are KSUIDs, so sorting by them orders events by time
+		ScanIndexForward: aws.Bool(true),
+		TableName:        aws.String(tableConsentHistory),
+	}
+
+	var representations []consentEventRepresentation
+	var unmarshalErr error
+	err := c.db.QueryPagesWithContext(ctx, input, func(output *dynamodb.QueryOutput, lastPage bool) bool {
+		var page []consentEventRepresentation
+		if unmarshalErr = dynamodbattribute.UnmarshalListOfMaps(output.Items, &page); unmarshalErr != nil {
+			return false
+		}
+
+		representations = append(representations, page...)
+
+		return true
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "execute query")
+	}
+
+	if unmarshalErr != nil {
+		return nil, errors.Wrap(unmarshalErr, "unmarshal query result")
+	}
+
+	events := make([]*consent.Event, len(representations))
+	for i := range representations {
+		if events[i], err = representations[i].toEvent(); err != nil {
+			return nil, errors.Wrapf(err, "convert consent event %q", representations[i].ID)
+		}
+	}
+
+	return events, nil
+}
+
+func migrateConsentHistoryTable(db *dynamodb.DynamoDB) error {
+	tables, err := db.ListTables(nil)
+	if err != nil {
+		return err
+	}
+
+	for _, table := range tables.TableNames {
+		if *table == tableConsentHistory {
+			return nil
+		}
+	}
+
+	_, err = db.CreateTable(&dynamodb.CreateTableInput{
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{AttributeName: aws.String("ID"), AttributeType: aws.String("S")},
+			{AttributeName: aws.String("SubjectID"), AttributeType: aws.String("S")},
+		},
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("ID"), KeyType: aws.String("HASH")},
+		},
+		GlobalSecondaryIndexes: []*dynamodb.GlobalSecondaryIndex{
+			{
+				IndexName: aws.String("ConsentHistorySubjectIndex"),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{AttributeName: aws.String("SubjectID"), KeyType: aws.String("HASH")},
+					{AttributeName: aws.String("ID"), KeyType: aws.String("RANGE")},
+				},
+				Projection: &dynamodb.Projection{
+					ProjectionType: aws.String("ALL"),
+				},
+				ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+					ReadCapacityUnits:  aws.Int64(5),
+					WriteCapacityUnits: aws.Int64(10),
+				},
+			},
+		},
+		ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(5),
+			WriteCapacityUnits: aws.Int64(10),
+		},
+		TableName: aws.String(tableConsentHistory),
+	})
+
+	return err
+}
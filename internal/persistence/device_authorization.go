@@ -0,0 +1,272 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/damejeras/auth/internal/device"
+	"github.com/pkg/errors"
+	"strconv"
+	"time"
+)
+
+const tableDeviceAuthorization = "oauth2_device_authorization"
+
+type deviceAuthorizationRepresentation struct {
+	DeviceCode   string
+	UserCode     string
+	ClientID     string
+	Scope        string
+	SubjectID    string
+	Denied       bool
+	Confirmation []byte
+	Interval     int64
+	PolledAt     int64
+	ExpiresAt    int64
+	CreatedAt    int64
+	UpdatedAt    int64
+}
+
+func (r deviceAuthorizationRepresentation) toAuthorization() (*device.Authorization, error) {
+	var confirmation *device.Confirmation
+	if err := unmarshalOptional(r.Confirmation, &confirmation); err != nil {
+		return nil, errors.Wrap(err, "unmarshal confirmation")
+	}
+
+	return &device.Authorization{
+		DeviceCode:   r.DeviceCode,
+		UserCode:     r.UserCode,
+		ClientID:     r.ClientID,
+		Scope:        r.Scope,
+		SubjectID:    r.SubjectID,
+		Denied:       r.Denied,
+		Confirmation: confirmation,
+		Interval:     time.Duration(r.Interval) * time.Second,
+		PolledAt:     time.Unix(r.PolledAt, 0),
+		ExpiresAt:    time.Unix(r.ExpiresAt, 0),
+		CreatedAt:    time.Unix(r.CreatedAt, 0),
+		UpdatedAt:    time.Unix(r.UpdatedAt, 0),
+	}, nil
+}
+
+type deviceAuthorizationRepository struct {
+	db *dynamodb.DynamoDB
+}
+
+func NewDeviceAuthorizationRepository(db *dynamodb.DynamoDB) (device.Repository, error) {
+	if err := migrateDeviceAuthorizationTable(db); err != nil {
+		return nil, errors.Wrap(err, "run table migration")
+	}
+
+	return &deviceAuthorizationRepository{db: db}, nil
+}
+
+func (d *deviceAuthorizationRepository) Store(ctx context.Context, authorization *device.Authorization) error {
+	_, err := d.db.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(tableDeviceAuthorization),
+		Item: map[string]*dynamodb.AttributeValue{
+			"DeviceCode": {S: aws.String(authorization.DeviceCode)},
+			"UserCode":   {S: aws.String(authorization.UserCode)},
+			"ClientID":   {S: aws.String(authorization.ClientID)},
+			"Scope":      {S: aws.String(authorization.Scope)},
+			"SubjectID":  {S: aws.String(authorization.SubjectID)},
+			"Denied":     {BOOL: aws.Bool(authorization.Denied)},
+			"Interval":   {N: aws.String(strconv.Itoa(int(authorization.Interval / time.Second)))},
+			"PolledAt":   {N: aws.String(strconv.Itoa(int(authorization.PolledAt.Unix())))},
+			"ExpiresAt":  {N: aws.String(strconv.Itoa(int(authorization.ExpiresAt.Unix())))},
+			"CreatedAt":  {N: aws.String(strconv.Itoa(int(time.Now().Unix())))},
+			"UpdatedAt":  {N: aws.String(strconv.Itoa(0))},
+		},
+	})
+
+	return errors.Wrap(err, "execute query")
+}
+
+func (d *deviceAuthorizationRepository) UpdateWithConfirmation(ctx context.Context, authorization *device.Authorization) error {
+	confirmation, err := json.Marshal(authorization.Confirmation)
+	if err != nil {
+		return errors.Wrap(err, "marshal confirmation")
+	}
+
+	_, err = d.db.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableDeviceAuthorization),
+		Key: map[string]*dynamodb.AttributeValue{
+			"DeviceCode": {S: aws.String(authorization.DeviceCode)},
+		},
+		UpdateExpression: aws.String("SET Confirmation = :Confirmation, UpdatedAt = :UpdatedAt"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":Confirmation": {B: confirmation},
+			":UpdatedAt":    {N: aws.String(strconv.Itoa(int(time.Now().Unix())))},
+		},
+	})
+
+	return errors.Wrap(err, "execute query")
+}
+
+func (d *deviceAuthorizationRepository) UpdateWithApproval(ctx context.Context, authorization *device.Authorization) error {
+	_, err := d.db.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableDeviceAuthorization),
+		Key: map[string]*dynamodb.AttributeValue{
+			"DeviceCode": {S: aws.String(authorization.DeviceCode)},
+		},
+		UpdateExpression: aws.String("SET SubjectID = :SubjectID, Scope = :Scope, UpdatedAt = :UpdatedAt"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":SubjectID": {S: aws.String(authorization.SubjectID)},
+			":Scope":     {S: aws.String(authorization.Scope)},
+			":UpdatedAt": {N: aws.String(strconv.Itoa(int(time.Now().Unix())))},
+		},
+	})
+
+	return errors.Wrap(err, "execute query")
+}
+
+func (d *deviceAuthorizationRepository) UpdateWithDenial(ctx context.Context, authorization *device.Authorization) error {
+	_, err := d.db.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableDeviceAuthorization),
+		Key: map[string]*dynamodb.AttributeValue{
+			"DeviceCode": {S: aws.String(authorization.DeviceCode)},
+		},
+		UpdateExpression: aws.String("SET Denied = :Denied, UpdatedAt = :UpdatedAt"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":Denied":    {BOOL: aws.Bool(authorization.Denied)},
+			":UpdatedAt": {N: aws.String(strconv.Itoa(int(time.Now().Unix())))},
+		},
+	})
+
+	return errors.Wrap(err, "execute query")
+}
+
+func (d *deviceAuthorizationRepository) UpdateWithPoll(ctx context.Context, authorization *device.Authorization) error {
+	_, err := d.db.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableDeviceAuthorization),
+		Key: map[string]*dynamodb.AttributeValue{
+			"DeviceCode": {S: aws.String(authorization.DeviceCode)},
+		},
+		UpdateExpression: aws.String("SET PolledAt = :PolledAt, #Interval = :Interval, UpdatedAt = :UpdatedAt"),
+		ExpressionAttributeNames: map[string]*string{
+			// INTERVAL is reserved word
+			"#Interval": aws.String("Interval"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":PolledAt":  {N: aws.String(strconv.Itoa(int(authorization.PolledAt.Unix())))},
+			":Interval":  {N: aws.String(strconv.Itoa(int(authorization.Interval / time.Second)))},
+			":UpdatedAt": {N: aws.String(strconv.Itoa(int(time.Now().Unix())))},
+		},
+	})
+
+	return errors.Wrap(err, "execute query")
+}
+
+func (d *deviceAuthorizationRepository) FindByDeviceCode(ctx context.Context, deviceCode string) (*device.Authorization, error) {
+	result, err := d.db.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(tableDeviceAuthorization),
+		Key: map[string]*dynamodb.AttributeValue{
+			"DeviceCode": {S: aws.String(deviceCode)},
+		},
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "execute query")
+	}
+
+	if len(result.Item) == 0 {
+		return nil, nil
+	}
+
+	var representation deviceAuthorizationRepresentation
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &representation); err != nil {
+		return nil, errors.Wrap(err, "unmarshal query result")
+	}
+
+	return representation.toAuthorization()
+}
+
+func (d *deviceAuthorizationRepository) FindByUserCode(ctx context.Context, userCode string) (*device.Authorization, error) {
+	result, err := d.db.QueryWithContext(ctx, &dynamodb.QueryInput{
+		IndexName: aws.String("DeviceUserCodeIndex"),
+		KeyConditions: map[string]*dynamodb.Condition{
+			"UserCode": {
+				ComparisonOperator: aws.String("EQ"),
+				AttributeValueList: []*dynamodb.AttributeValue{
+					{
+						S: aws.String(userCode),
+					},
+				},
+			},
+		},
+		TableName: aws.String(tableDeviceAuthorization),
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "execute query")
+	}
+
+	if len(result.Items) == 0 {
+		return nil, nil
+	}
+
+	var representation deviceAuthorizationRepresentation
+	if err := dynamodbattribute.UnmarshalMap(result.Items[0], &representation); err != nil {
+		return nil, errors.Wrap(err, "unmarshal query result")
+	}
+
+	return representation.toAuthorization()
+}
+
+func (d *deviceAuthorizationRepository) Delete(ctx context.Context, authorization *device.Authorization) error {
+	_, err := d.db.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(tableDeviceAuthorization),
+		Key: map[string]*dynamodb.AttributeValue{
+			"DeviceCode": {S: aws.String(authorization.DeviceCode)},
+		},
+	})
+
+	return errors.Wrap(err, "execute query")
+}
+
+func migrateDeviceAuthorizationTable(db *dynamodb.DynamoDB) error {
+	tables, err := db.ListTables(nil)
+	if err != nil {
+		return err
+	}
+
+	for _, table := range tables.TableNames {
+		if *table == tableDeviceAuthorization {
+			return nil
+		}
+	}
+
+	_, err = db.CreateTable(&dynamodb.CreateTableInput{
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{AttributeName: aws.String("DeviceCode"), AttributeType: aws.String("S")},
+			{AttributeName: aws.String("UserCode"), AttributeType: aws.String("S")},
+		},
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("DeviceCode"), KeyType: aws.String("HASH")},
+		},
+		GlobalSecondaryIndexes: []*dynamodb.GlobalSecondaryIndex{
+			{
+				IndexName: aws.String("DeviceUserCodeIndex"),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{AttributeName: aws.String("UserCode"), KeyType: aws.String("HASH")},
+				},
+				Projection: &dynamodb.Projection{
+					ProjectionType: aws.String("ALL"),
+				},
+				ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+					ReadCapacityUnits:  aws.Int64(5),
+					WriteCapacityUnits: aws.Int64(10),
+				},
+			},
+		},
+		ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(5),
+			WriteCapacityUnits: aws.Int64(10),
+		},
+		TableName: aws.String(tableDeviceAuthorization),
+	})
+
+	return err
+}
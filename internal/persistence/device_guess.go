@@ -0,0 +1,99 @@
+package persistence
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/damejeras/auth/internal/device"
+	"github.com/pkg/errors"
+	"strconv"
+	"time"
+)
+
+const tableDeviceGuess = "oauth2_device_guess"
+
+// deviceGuessRepository keeps counter per source IP address and window, expired counters are deleted by DynamoDB.
+type deviceGuessRepository struct {
+	db *dynamodb.DynamoDB
+}
+
+func NewDeviceGuessRepository(db *dynamodb.DynamoDB) (device.GuessRepository, error) {
+	if err := migrateDeviceGuessTable(db); err != nil {
+		return nil, errors.Wrap(err, "run table migration")
+	}
+
+	return &deviceGuessRepository{db: db}, nil
+}
+
+func (d *deviceGuessRepository) Count(ctx context.Context, ipAddress string, window time.Time) (int, error) {
+	result, err := d.db.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(tableDeviceGuess),
+		Key: map[string]*dynamodb.AttributeValue{
+			"ID": {S: aws.String(guessID(ipAddress, window))},
+		},
+	})
+
+	if err != nil {
+		return 0, errors.Wrap(err, "execute query")
+	}
+
+	guesses, ok := result.Item["Guesses"]
+	if !ok {
+		return 0, nil
+	}
+
+	count, err := strconv.Atoi(aws.StringValue(guesses.N))
+
+	return count, errors.Wrap(err, "parse guess count")
+}
+
+func (d *deviceGuessRepository) Add(ctx context.Context, ipAddress string, window, expiresAt time.Time) error {
+	_, err := d.db.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableDeviceGuess),
+		Key: map[string]*dynamodb.AttributeValue{
+			"ID": {S: aws.String(guessID(ipAddress, window))},
+		},
+		UpdateExpression: aws.String("ADD Guesses :One SET ExpiresAt = :ExpiresAt"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":One":       {N: aws.String("1")},
+			":ExpiresAt": {N: aws.String(strconv.Itoa(int(expiresAt.Unix())))},
+		},
+	})
+
+	return errors.Wrap(err, "execute query")
+}
+
+func guessID(ipAddress string, window time.Time) string {
+	return ipAddress + "/" + strconv.Itoa(int(window.Unix()))
+}
+
+func migrateDeviceGuessTable(db *dynamodb.DynamoDB) error {
+	tables, err := db.ListTables(nil)
+	if err != nil {
+		return err
+	}
+
+	for _, table := range tables.TableNames {
+		if *table == tableDeviceGuess {
+			return enableTimeToLive(db, tableDeviceGuess, "ExpiresAt")
+		}
+	}
+
+	if _, err = db.CreateTable(&dynamodb.CreateTableInput{
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{AttributeName: aws.String("ID"), AttributeType: aws.String("S")},
+		},
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("ID"), KeyType: aws.String("HASH")},
+		},
+		ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(5),
+			WriteCapacityUnits: aws.Int64(10),
+		},
+		TableName: aws.String(tableDeviceGuess),
+	}); err != nil {
+		return err
+	}
+
+	return enableTimeToLive(db, tableDeviceGuess, "ExpiresAt")
+}
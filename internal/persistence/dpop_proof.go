@@ -0,0 +1,79 @@
+package persistence
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/damejeras/auth/internal/dpop"
+	"github.com/pkg/errors"
+	"strconv"
+	"time"
+)
+
+const tableDPoPProof = "oauth2_dpop_proof"
+
+// dpopProofRepository keeps proofs in table with time to live on ExpiresAt, so replay cache does not grow unbounded.
+type dpopProofRepository struct {
+	db *dynamodb.DynamoDB
+}
+
+func NewDPoPProofRepository(db *dynamodb.DynamoDB) (dpop.Repository, error) {
+	if err := migrateDPoPProofTable(db); err != nil {
+		return nil, errors.Wrap(err, "run table migration")
+	}
+
+	return &dpopProofRepository{db: db}, nil
+}
+
+// Store puts proof only if it is not stored yet, so concurrent requests can not use the same proof.
+func (d *dpopProofRepository) Store(ctx context.Context, proof *dpop.Proof) error {
+	_, err := d.db.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(tableDPoPProof),
+		Item: map[string]*dynamodb.AttributeValue{
+			"ID":            {S: aws.String(proof.ID)},
+			"KeyThumbprint": {S: aws.String(proof.KeyThumbprint)},
+			"ExpiresAt":     {N: aws.String(strconv.Itoa(int(proof.ExpiresAt.Unix())))},
+			"CreatedAt":     {N: aws.String(strconv.Itoa(int(time.Now().Unix())))},
+		},
+		ConditionExpression: aws.String("attribute_not_exists(ID)"),
+	})
+
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+		return dpop.ErrProofReplayed
+	}
+
+	return errors.Wrap(err, "execute query")
+}
+
+func migrateDPoPProofTable(db *dynamodb.DynamoDB) error {
+	tables, err := db.ListTables(nil)
+	if err != nil {
+		return err
+	}
+
+	for _, table := range tables.TableNames {
+		if *table == tableDPoPProof {
+			return enableTimeToLive(db, tableDPoPProof, "ExpiresAt")
+		}
+	}
+
+	if _, err = db.CreateTable(&dynamodb.CreateTableInput{
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{AttributeName: aws.String("ID"), AttributeType: aws.String("S")},
+		},
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("ID"), KeyType: aws.String("HASH")},
+		},
+		ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(5),
+			WriteCapacityUnits: aws.Int64(10),
+		},
+		TableName: aws.String(tableDPoPProof),
+	}); err != nil {
+		return err
+	}
+
+	// expired proofs are deleted by DynamoDB, they can not be accepted anyway
+	return enableTimeToLive(db, tableDPoPProof, "ExpiresAt")
+}
@@ -1,12 +1,14 @@
 package persistence
 
 import (
+	"encoding/json"
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/damejeras/auth/internal/app"
 	"log"
+	"time"
 )
 
 func NewDynamoDBClient(cfg *app.Config) *dynamodb.DynamoDB {
@@ -23,3 +25,74 @@ func NewDynamoDBClient(cfg *app.Config) *dynamodb.DynamoDB {
 
 	return dynamodb.New(awsSession)
 }
+
+// migrateGlobalSecondaryIndex adds index to already existing table unless table has it.
+func migrateGlobalSecondaryIndex(db *dynamodb.DynamoDB, table string, index *dynamodb.GlobalSecondaryIndex, attributes ...*dynamodb.AttributeDefinition) error {
+	description, err := db.DescribeTable(&dynamodb.DescribeTableInput{TableName: aws.String(table)})
+	if err != nil {
+		return err
+	}
+
+	for _, existing := range description.Table.GlobalSecondaryIndexes {
+		if *existing.IndexName == *index.IndexName {
+			return nil
+		}
+	}
+
+	_, err = db.UpdateTable(&dynamodb.UpdateTableInput{
+		TableName:            aws.String(table),
+		AttributeDefinitions: attributes,
+		GlobalSecondaryIndexUpdates: []*dynamodb.GlobalSecondaryIndexUpdate{
+			{
+				Create: &dynamodb.CreateGlobalSecondaryIndexAction{
+					IndexName:             index.IndexName,
+					KeySchema:             index.KeySchema,
+					Projection:            index.Projection,
+					ProvisionedThroughput: index.ProvisionedThroughput,
+				},
+			},
+		},
+	})
+
+	return err
+}
+
+// enableTimeToLive makes DynamoDB delete items once unix time in given attribute passes, unless table already has it.
+func enableTimeToLive(db *dynamodb.DynamoDB, table, attribute string) error {
+	description, err := db.DescribeTimeToLive(&dynamodb.DescribeTimeToLiveInput{TableName: aws.String(table)})
+	if err != nil {
+		return err
+	}
+
+	if status := description.TimeToLiveDescription; status != nil && aws.StringValue(status.TimeToLiveStatus) != dynamodb.TimeToLiveStatusDisabled {
+		return nil
+	}
+
+	_, err = db.UpdateTimeToLive(&dynamodb.UpdateTimeToLiveInput{
+		TableName: aws.String(table),
+		TimeToLiveSpecification: &dynamodb.TimeToLiveSpecification{
+			AttributeName: aws.String(attribute),
+			Enabled:       aws.Bool(true),
+		},
+	})
+
+	return err
+}
+
+// unmarshalOptional unmarshals JSON attribute, which items stored before the attribute was introduced do not have.
+func unmarshalOptional(data []byte, v interface{}) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(data, v)
+}
+
+// optionalUnix stores zero time as zero, so it is read back as absent value instead of distant past.
+func optionalUnix(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.Unix()
+}
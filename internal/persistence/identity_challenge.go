@@ -16,9 +16,10 @@ import (
 const tableIdentityChallenge = "oauth_identity_challenge"
 
 type challengeRepresentation struct {
-	ID, ClientID, Verifier       string
-	ChallengeIdentity, Footprint []byte
-	CreatedAt, UpdatedAt         int64
+	ID, ClientID, Verifier                              string
+	ChallengeIdentity, Parameters, Rejection, Footprint []byte
+	SessionExists                                       bool
+	CreatedAt, UpdatedAt                                int64
 }
 
 type identityChallengeRepository struct {
@@ -39,6 +40,16 @@ func (r *identityChallengeRepository) Store(ctx context.Context, challenge *iden
 		return errors.Wrap(err, "marshal authorization")
 	}
 
+	parametersBytes, err := json.Marshal(challenge.Parameters)
+	if err != nil {
+		return errors.Wrap(err, "marshal parameters")
+	}
+
+	rejectionBytes, err := json.Marshal(challenge.Rejection)
+	if err != nil {
+		return errors.Wrap(err, "marshal rejection")
+	}
+
 	footprintBytes, err := json.Marshal(challenge.Footprint)
 	if err != nil {
 		return errors.Wrap(err, "marshal footprint bytes")
@@ -51,7 +62,10 @@ func (r *identityChallengeRepository) Store(ctx context.Context, challenge *iden
 			"ClientID":          {S: aws.String(challenge.ClientID)},
 			"Verifier":          {S: aws.String(challenge.Verifier)},
 			"ChallengeIdentity": {B: identityBytes},
+			"Parameters":        {B: parametersBytes},
+			"Rejection":         {B: rejectionBytes},
 			"Footprint":         {B: footprintBytes},
+			"SessionExists":     {BOOL: aws.Bool(challenge.SessionExists)},
 			"CreatedAt":         {N: aws.String(strconv.Itoa(int(time.Now().Unix())))},
 			"UpdatedAt":         {N: aws.String(strconv.Itoa(0))},
 		},
@@ -81,6 +95,27 @@ func (r *identityChallengeRepository) UpdateWithAuthorization(ctx context.Contex
 	return errors.Wrap(err, "execute query")
 }
 
+func (r *identityChallengeRepository) UpdateWithRejection(ctx context.Context, challenge *identity.Challenge) error {
+	rejectionBytes, err := json.Marshal(challenge.Rejection)
+	if err != nil {
+		return errors.Wrap(err, "marshal rejection")
+	}
+
+	_, err = r.db.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableIdentityChallenge),
+		Key: map[string]*dynamodb.AttributeValue{
+			"ID": {S: aws.String(challenge.ID)},
+		},
+		UpdateExpression: aws.String("SET Rejection = :bytes, UpdatedAt = :timestamp"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":bytes":     {B: rejectionBytes},
+			":timestamp": {N: aws.String(strconv.Itoa(int(time.Now().Unix())))},
+		},
+	})
+
+	return errors.Wrap(err, "execute query")
+}
+
 func (r *identityChallengeRepository) Delete(ctx context.Context, challenge *identity.Challenge) error {
 	_, err := r.db.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
 		TableName: aws.String(tableIdentityChallenge),
@@ -114,23 +149,36 @@ func (r *identityChallengeRepository) FindByID(ctx context.Context, id string) (
 	}
 
 	var authorization identity.Identity
+	var parameters identity.Parameters
+	var rejection *identity.Rejection
 	var footprint integrity.Footprint
 	if err := json.Unmarshal(representation.ChallengeIdentity, &authorization); err != nil {
 		return nil, errors.Wrap(err, "unmarshal authorization")
 	}
 
+	if err := json.Unmarshal(representation.Parameters, &parameters); err != nil {
+		return nil, errors.Wrap(err, "unmarshal parameters")
+	}
+
+	if err := json.Unmarshal(representation.Rejection, &rejection); err != nil {
+		return nil, errors.Wrap(err, "unmarshal rejection")
+	}
+
 	if err := json.Unmarshal(representation.Footprint, &footprint); err != nil {
 		return nil, errors.Wrap(err, "unmarshal footprint")
 	}
 
 	return &identity.Challenge{
-		ID:        representation.ID,
-		ClientID:  representation.ClientID,
-		Verifier:  representation.Verifier,
-		Identity:  &authorization,
-		Footprint: &footprint,
-		CreatedAt: time.Unix(representation.CreatedAt, 0),
-		UpdatedAt: time.Unix(representation.UpdatedAt, 0),
+		ID:            representation.ID,
+		ClientID:      representation.ClientID,
+		Verifier:      representation.Verifier,
+		Identity:      &authorization,
+		Parameters:    &parameters,
+		Rejection:     rejection,
+		Footprint:     &footprint,
+		SessionExists: representation.SessionExists,
+		CreatedAt:     time.Unix(representation.CreatedAt, 0),
+		UpdatedAt:     time.Unix(representation.UpdatedAt, 0),
 	}, nil
 }
 
@@ -169,23 +217,36 @@ func (r *identityChallengeRepository) FindByVerifier(ctx context.Context, verifi
 	}
 
 	var authorization identity.Identity
+	var parameters identity.Parameters
+	var rejection *identity.Rejection
 	var footprint integrity.Footprint
 	if err := json.Unmarshal(representation.ChallengeIdentity, &authorization); err != nil {
 		return nil, errors.Wrap(err, "unmarshal authorization")
 	}
 
+	if err := json.Unmarshal(representation.Parameters, &parameters); err != nil {
+		return nil, errors.Wrap(err, "unmarshal parameters")
+	}
+
+	if err := json.Unmarshal(representation.Rejection, &rejection); err != nil {
+		return nil, errors.Wrap(err, "unmarshal rejection")
+	}
+
 	if err := json.Unmarshal(representation.Footprint, &footprint); err != nil {
 		return nil, errors.Wrap(err, "unmarshal footprint")
 	}
 
 	return &identity.Challenge{
-		ID:        representation.ID,
-		ClientID:  representation.ClientID,
-		Verifier:  representation.Verifier,
-		Identity:  &authorization,
-		Footprint: &footprint,
-		CreatedAt: time.Unix(representation.CreatedAt, 0),
-		UpdatedAt: time.Unix(representation.UpdatedAt, 0),
+		ID:            representation.ID,
+		ClientID:      representation.ClientID,
+		Verifier:      representation.Verifier,
+		Identity:      &authorization,
+		Parameters:    &parameters,
+		Rejection:     rejection,
+		Footprint:     &footprint,
+		SessionExists: representation.SessionExists,
+		CreatedAt:     time.Unix(representation.CreatedAt, 0),
+		UpdatedAt:     time.Unix(representation.UpdatedAt, 0),
 	}, nil
 }
 
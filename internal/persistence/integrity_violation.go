@@ -0,0 +1,276 @@
+package persistence
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/damejeras/auth/internal/integrity"
+	"github.com/pkg/errors"
+	"strconv"
+	"time"
+)
+
+const tableIntegrityViolation = "oauth2_integrity_violation"
+
+// violations are listed by creation time, so all of them share the same partition of creation index.
+// They are rare, so the partition does not get hot.
+const (
+	indexViolationCreated = "ViolationCreatedIndex"
+	violationPartition    = "violation"
+)
+
+// violationIndexes are used to count violations by attribute lockout of the kind is counted by.
+var violationIndexes = map[string]struct {
+	name      string
+	attribute string
+}{
+	integrity.LockoutSubject:   {name: "ViolationSubjectIndex", attribute: "SubjectID"},
+	integrity.LockoutClient:    {name: "ViolationClientIndex", attribute: "ClientID"},
+	integrity.LockoutIPAddress: {name: "ViolationIPAddressIndex", attribute: "IPAddress"},
+}
+
+type violationRepresentation struct {
+	ID          string
+	Type        string
+	Description string
+	ChallengeID string
+	ClientID    string
+	SubjectID   string
+	RequestID   string
+	IPAddress   string
+	UserAgent   string
+	Referer     string
+	ExpiresAt   int64
+	CreatedAt   int64
+}
+
+func (r violationRepresentation) toViolation() *integrity.Violation {
+	violation := integrity.Violation{
+		ID:          r.ID,
+		Type:        r.Type,
+		Description: r.Description,
+		ChallengeID: r.ChallengeID,
+		ClientID:    r.ClientID,
+		SubjectID:   r.SubjectID,
+		RequestID:   r.RequestID,
+		IPAddress:   r.IPAddress,
+		UserAgent:   r.UserAgent,
+		Referer:     r.Referer,
+		CreatedAt:   time.Unix(r.CreatedAt, 0),
+	}
+
+	// items stored before retention was introduced do not have the attribute
+	if r.ExpiresAt != 0 {
+		violation.ExpiresAt = time.Unix(r.ExpiresAt, 0)
+	}
+
+	return &violation
+}
+
+type violationRepository struct {
+	db *dynamodb.DynamoDB
+}
+
+func NewViolationRepository(db *dynamodb.DynamoDB) (integrity.ViolationRepository, error) {
+	if err := migrateViolationTable(db); err != nil {
+		return nil, errors.Wrap(err, "run table migration")
+	}
+
+	return &violationRepository{db: db}, nil
+}
+
+func (v *violationRepository) Store(ctx context.Context, violation *integrity.Violation) error {
+	item := map[string]*dynamodb.AttributeValue{
+		"ID":          {S: aws.String(violation.ID)},
+		"Type":        {S: aws.String(violation.Type)},
+		"Description": {S: aws.String(violation.Description)},
+		"ChallengeID": {S: aws.String(violation.ChallengeID)},
+		"RequestID":   {S: aws.String(violation.RequestID)},
+		"UserAgent":   {S: aws.String(violation.UserAgent)},
+		"Referer":     {S: aws.String(violation.Referer)},
+		"Partition":   {S: aws.String(violationPartition)},
+		"ExpiresAt":   {N: aws.String(strconv.Itoa(int(optionalUnix(violation.ExpiresAt))))},
+		"CreatedAt":   {N: aws.String(strconv.Itoa(int(violation.CreatedAt.Unix())))},
+	}
+
+	// index keys can not be empty, unknown attributes are left out of the indexes
+	for attribute, value := range map[string]string{
+		"SubjectID": violation.SubjectID,
+		"ClientID":  violation.ClientID,
+		"IPAddress": violation.IPAddress,
+	} {
+		if value != "" {
+			item[attribute] = &dynamodb.AttributeValue{S: aws.String(value)}
+		}
+	}
+
+	_, err := v.db.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(tableIntegrityViolation),
+		Item:      item,
+	})
+
+	return errors.Wrap(err, "execute query")
+}
+
+func (v *violationRepository) FindCreatedAfter(ctx context.Context, since time.Time) ([]*integrity.Violation, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(tableIntegrityViolation),
+		IndexName:              aws.String(indexViolationCreated),
+		KeyConditionExpression: aws.String("#Partition = :Partition AND CreatedAt >= :Since"),
+		ExpressionAttributeNames: map[string]*string{
+			// PARTITION is reserved word
+			"#Partition": aws.String("Partition"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":Partition": {S: aws.String(violationPartition)},
+			":Since":     {N: aws.String(strconv.Itoa(int(since.Unix())))},
+		},
+	}
+
+	var representations []violationRepresentation
+	var unmarshalErr error
+	err := v.db.QueryPagesWithContext(ctx, input, func(output *dynamodb.QueryOutput, lastPage bool) bool {
+		var page []violationRepresentation
+		if unmarshalErr = dynamodbattribute.UnmarshalListOfMaps(output.Items, &page); unmarshalErr != nil {
+			return false
+		}
+
+		representations = append(representations, page...)
+
+		return true
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "execute query")
+	}
+
+	if unmarshalErr != nil {
+		return nil, errors.Wrap(unmarshalErr, "unmarshal query result")
+	}
+
+	violations := make([]*integrity.Violation, len(representations))
+	for i := range representations {
+		violations[i] = representations[i].toViolation()
+	}
+
+	return violations, nil
+}
+
+func (v *violationRepository) CountCreatedAfter(ctx context.Context, kind, value string, since time.Time) (int, error) {
+	index, ok := violationIndexes[kind]
+	if !ok {
+		return 0, errors.Errorf("unknown lockout kind %q", kind)
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(tableIntegrityViolation),
+		IndexName:              aws.String(index.name),
+		Select:                 aws.String(dynamodb.SelectCount),
+		KeyConditionExpression: aws.String("#Attribute = :Value AND CreatedAt >= :Since"),
+		ExpressionAttributeNames: map[string]*string{
+			"#Attribute": aws.String(index.attribute),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":Value": {S: aws.String(value)},
+			":Since": {N: aws.String(strconv.Itoa(int(since.Unix())))},
+		},
+	}
+
+	var count int
+	err := v.db.QueryPagesWithContext(ctx, input, func(output *dynamodb.QueryOutput, lastPage bool) bool {
+		count += int(aws.Int64Value(output.Count))
+
+		return true
+	})
+
+	return count, errors.Wrap(err, "execute query")
+}
+
+func migrateViolationTable(db *dynamodb.DynamoDB) error {
+	createdAt := &dynamodb.AttributeDefinition{AttributeName: aws.String("CreatedAt"), AttributeType: aws.String("N")}
+	attributes := []*dynamodb.AttributeDefinition{
+		{AttributeName: aws.String("ID"), AttributeType: aws.String("S")},
+		createdAt,
+	}
+
+	indexes := make([]*dynamodb.GlobalSecondaryIndex, 0, len(violationIndexes))
+	for _, index := range violationIndexes {
+		attributes = append(attributes, &dynamodb.AttributeDefinition{
+			AttributeName: aws.String(index.attribute), AttributeType: aws.String("S"),
+		})
+
+		indexes = append(indexes, &dynamodb.GlobalSecondaryIndex{
+			IndexName: aws.String(index.name),
+			KeySchema: []*dynamodb.KeySchemaElement{
+				{AttributeName: aws.String(index.attribute), KeyType: aws.String("HASH")},
+				{AttributeName: aws.String("CreatedAt"), KeyType: aws.String("RANGE")},
+			},
+			Projection: &dynamodb.Projection{
+				ProjectionType: aws.String("KEYS_ONLY"),
+			},
+			ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+				ReadCapacityUnits:  aws.Int64(5),
+				WriteCapacityUnits: aws.Int64(10),
+			},
+		})
+	}
+
+	// creation index projects all attributes, as violations are listed from it
+	attributes = append(attributes, &dynamodb.AttributeDefinition{
+		AttributeName: aws.String("Partition"), AttributeType: aws.String("S"),
+	})
+
+	indexes = append(indexes, &dynamodb.GlobalSecondaryIndex{
+		IndexName: aws.String(indexViolationCreated),
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("Partition"), KeyType: aws.String("HASH")},
+			{AttributeName: aws.String("CreatedAt"), KeyType: aws.String("RANGE")},
+		},
+		Projection: &dynamodb.Projection{
+			ProjectionType: aws.String("ALL"),
+		},
+		ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(5),
+			WriteCapacityUnits: aws.Int64(10),
+		},
+	})
+
+	tables, err := db.ListTables(nil)
+	if err != nil {
+		return err
+	}
+
+	for _, table := range tables.TableNames {
+		if *table != tableIntegrityViolation {
+			continue
+		}
+
+		for _, index := range indexes {
+			if err := migrateGlobalSecondaryIndex(db, tableIntegrityViolation, index, createdAt, &dynamodb.AttributeDefinition{
+				AttributeName: index.KeySchema[0].AttributeName, AttributeType: aws.String("S"),
+			}); err != nil {
+				return err
+			}
+		}
+
+		return enableTimeToLive(db, tableIntegrityViolation, "ExpiresAt")
+	}
+
+	if _, err = db.CreateTable(&dynamodb.CreateTableInput{
+		AttributeDefinitions:   attributes,
+		GlobalSecondaryIndexes: indexes,
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("ID"), KeyType: aws.String("HASH")},
+		},
+		ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(5),
+			WriteCapacityUnits: aws.Int64(10),
+		},
+		TableName: aws.String(tableIntegrityViolation),
+	}); err != nil {
+		return err
+	}
+
+	return enableTimeToLive(db, tableIntegrityViolation, "ExpiresAt")
+}
@@ -0,0 +1,153 @@
+package persistence
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/damejeras/auth/internal/integrity"
+	"github.com/pkg/errors"
+	"strconv"
+	"time"
+)
+
+const tableLockout = "oauth2_lockout"
+
+type lockoutRepresentation struct {
+	Kind       string
+	Value      string
+	Violations int
+	ExpiresAt  int64
+	CreatedAt  int64
+}
+
+func (r lockoutRepresentation) toLockout() *integrity.Lockout {
+	return &integrity.Lockout{
+		Kind:       r.Kind,
+		Value:      r.Value,
+		Violations: r.Violations,
+		ExpiresAt:  time.Unix(r.ExpiresAt, 0),
+		CreatedAt:  time.Unix(r.CreatedAt, 0),
+	}
+}
+
+type lockoutRepository struct {
+	db *dynamodb.DynamoDB
+}
+
+func NewLockoutRepository(db *dynamodb.DynamoDB) (integrity.LockoutRepository, error) {
+	if err := migrateLockoutTable(db); err != nil {
+		return nil, errors.Wrap(err, "run table migration")
+	}
+
+	return &lockoutRepository{db: db}, nil
+}
+
+func (l *lockoutRepository) Store(ctx context.Context, lockout *integrity.Lockout) error {
+	_, err := l.db.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(tableLockout),
+		Item: map[string]*dynamodb.AttributeValue{
+			"Kind":       {S: aws.String(lockout.Kind)},
+			"Value":      {S: aws.String(lockout.Value)},
+			"Violations": {N: aws.String(strconv.Itoa(lockout.Violations))},
+			"ExpiresAt":  {N: aws.String(strconv.Itoa(int(lockout.ExpiresAt.Unix())))},
+			"CreatedAt":  {N: aws.String(strconv.Itoa(int(lockout.CreatedAt.Unix())))},
+		},
+	})
+
+	return errors.Wrap(err, "execute query")
+}
+
+func (l *lockoutRepository) Find(ctx context.Context, kind, value string) (*integrity.Lockout, error) {
+	result, err := l.db.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(tableLockout),
+		Key: map[string]*dynamodb.AttributeValue{
+			"Kind":  {S: aws.String(kind)},
+			"Value": {S: aws.String(value)},
+		},
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "execute query")
+	}
+
+	if len(result.Item) == 0 {
+		return nil, nil
+	}
+
+	var representation lockoutRepresentation
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &representation); err != nil {
+		return nil, errors.Wrap(err, "unmarshal query result")
+	}
+
+	return representation.toLockout(), nil
+}
+
+func (l *lockoutRepository) FindActive(ctx context.Context) ([]*integrity.Lockout, error) {
+	input := &dynamodb.ScanInput{
+		TableName:        aws.String(tableLockout),
+		FilterExpression: aws.String("ExpiresAt > :Now"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":Now": {N: aws.String(strconv.Itoa(int(time.Now().Unix())))},
+		},
+	}
+
+	var representations []lockoutRepresentation
+	var unmarshalErr error
+	err := l.db.ScanPagesWithContext(ctx, input, func(output *dynamodb.ScanOutput, lastPage bool) bool {
+		var page []lockoutRepresentation
+		if unmarshalErr = dynamodbattribute.UnmarshalListOfMaps(output.Items, &page); unmarshalErr != nil {
+			return false
+		}
+
+		representations = append(representations, page...)
+
+		return true
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "execute query")
+	}
+
+	if unmarshalErr != nil {
+		return nil, errors.Wrap(unmarshalErr, "unmarshal query result")
+	}
+
+	lockouts := make([]*integrity.Lockout, len(representations))
+	for i := range representations {
+		lockouts[i] = representations[i].toLockout()
+	}
+
+	return lockouts, nil
+}
+
+func migrateLockoutTable(db *dynamodb.DynamoDB) error {
+	tables, err := db.ListTables(nil)
+	if err != nil {
+		return err
+	}
+
+	for _, table := range tables.TableNames {
+		if *table == tableLockout {
+			return nil
+		}
+	}
+
+	_, err = db.CreateTable(&dynamodb.CreateTableInput{
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{AttributeName: aws.String("Kind"), AttributeType: aws.String("S")},
+			{AttributeName: aws.String("Value"), AttributeType: aws.String("S")},
+		},
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("Kind"), KeyType: aws.String("HASH")},
+			{AttributeName: aws.String("Value"), KeyType: aws.String("RANGE")},
+		},
+		ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(5),
+			WriteCapacityUnits: aws.Int64(10),
+		},
+		TableName: aws.String(tableLockout),
+	})
+
+	return err
+}
@@ -0,0 +1,149 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/damejeras/auth/internal/par"
+	"github.com/pkg/errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const tablePushedRequest = "oauth2_pushed_request"
+
+type pushedRequestRepresentation struct {
+	ID         string
+	ClientID   string
+	Parameters []byte
+	Used       bool
+	ExpiresAt  int64
+	CreatedAt  int64
+	UpdatedAt  int64
+}
+
+type pushedRequestRepository struct {
+	db *dynamodb.DynamoDB
+}
+
+func NewPushedRequestRepository(db *dynamodb.DynamoDB) (par.Repository, error) {
+	if err := migratePushedRequestTable(db); err != nil {
+		return nil, errors.Wrap(err, "run table migration")
+	}
+
+	return &pushedRequestRepository{db: db}, nil
+}
+
+func (p *pushedRequestRepository) Store(ctx context.Context, request *par.Request) error {
+	parametersBytes, err := json.Marshal(request.Parameters)
+	if err != nil {
+		return errors.Wrap(err, "marshal parameters")
+	}
+
+	_, err = p.db.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(tablePushedRequest),
+		Item: map[string]*dynamodb.AttributeValue{
+			"ID":         {S: aws.String(request.ID)},
+			"ClientID":   {S: aws.String(request.ClientID)},
+			"Parameters": {B: parametersBytes},
+			"Used":       {BOOL: aws.Bool(request.Used)},
+			"ExpiresAt":  {N: aws.String(strconv.Itoa(int(request.ExpiresAt.Unix())))},
+			"CreatedAt":  {N: aws.String(strconv.Itoa(int(time.Now().Unix())))},
+			"UpdatedAt":  {N: aws.String(strconv.Itoa(0))},
+		},
+	})
+
+	return errors.Wrap(err, "execute query")
+}
+
+func (p *pushedRequestRepository) UpdateWithUsage(ctx context.Context, request *par.Request) error {
+	_, err := p.db.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(tablePushedRequest),
+		Key: map[string]*dynamodb.AttributeValue{
+			"ID": {S: aws.String(request.ID)},
+		},
+		UpdateExpression:    aws.String("SET Used = :Used, UpdatedAt = :UpdatedAt"),
+		ConditionExpression: aws.String("Used = :false"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":Used":      {BOOL: aws.Bool(request.Used)},
+			":UpdatedAt": {N: aws.String(strconv.Itoa(int(time.Now().Unix())))},
+			":false":     {BOOL: aws.Bool(false)},
+		},
+	})
+
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+		return par.ErrRequestUsed
+	}
+
+	return errors.Wrap(err, "execute query")
+}
+
+func (p *pushedRequestRepository) FindByID(ctx context.Context, id string) (*par.Request, error) {
+	result, err := p.db.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(tablePushedRequest),
+		Key: map[string]*dynamodb.AttributeValue{
+			"ID": {S: aws.String(id)},
+		},
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "execute query")
+	}
+
+	if len(result.Item) == 0 {
+		return nil, nil
+	}
+
+	var representation pushedRequestRepresentation
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &representation); err != nil {
+		return nil, errors.Wrap(err, "unmarshal query result")
+	}
+
+	var parameters url.Values
+	if err := json.Unmarshal(representation.Parameters, &parameters); err != nil {
+		return nil, errors.Wrap(err, "unmarshal parameters")
+	}
+
+	return &par.Request{
+		ID:         representation.ID,
+		ClientID:   representation.ClientID,
+		Parameters: parameters,
+		Used:       representation.Used,
+		ExpiresAt:  time.Unix(representation.ExpiresAt, 0),
+		CreatedAt:  time.Unix(representation.CreatedAt, 0),
+		UpdatedAt:  time.Unix(representation.UpdatedAt, 0),
+	}, nil
+}
+
+func migratePushedRequestTable(db *dynamodb.DynamoDB) error {
+	tables, err := db.ListTables(nil)
+	if err != nil {
+		return err
+	}
+
+	for _, table := range tables.TableNames {
+		if *table == tablePushedRequest {
+			return nil
+		}
+	}
+
+	_, err = db.CreateTable(&dynamodb.CreateTableInput{
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{AttributeName: aws.String("ID"), AttributeType: aws.String("S")},
+		},
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("ID"), KeyType: aws.String("HASH")},
+		},
+		ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(5),
+			WriteCapacityUnits: aws.Int64(10),
+		},
+		TableName: aws.String(tablePushedRequest),
+	})
+
+	return err
+}
@@ -0,0 +1,219 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/damejeras/auth/internal/scope"
+	"github.com/pkg/errors"
+	"strconv"
+	"time"
+)
+
+const tableScope = "oauth2_scope"
+
+type scopeRepresentation struct {
+	Name            string
+	DisplayName     string
+	Descriptions    []byte
+	RequiresConsent bool
+	Sensitive       bool
+	Implies         []byte
+	CreatedAt       int64
+	UpdatedAt       int64
+}
+
+func (r scopeRepresentation) toScope() (*scope.Scope, error) {
+	var descriptions map[string]string
+	if err := json.Unmarshal(r.Descriptions, &descriptions); err != nil {
+		return nil, errors.Wrap(err, "unmarshal descriptions")
+	}
+
+	var implies []string
+	if err := unmarshalOptional(r.Implies, &implies); err != nil {
+		return nil, errors.Wrap(err, "unmarshal implied scopes")
+	}
+
+	return &scope.Scope{
+		Name:            r.Name,
+		DisplayName:     r.DisplayName,
+		Descriptions:    descriptions,
+		RequiresConsent: r.RequiresConsent,
+		Sensitive:       r.Sensitive,
+		Implies:         implies,
+		CreatedAt:       time.Unix(r.CreatedAt, 0),
+		UpdatedAt:       time.Unix(r.UpdatedAt, 0),
+	}, nil
+}
+
+type scopeRepository struct {
+	db *dynamodb.DynamoDB
+}
+
+func NewScopeRepository(db *dynamodb.DynamoDB) (scope.Repository, error) {
+	if err := migrateScopeTable(db); err != nil {
+		return nil, errors.Wrap(err, "run table migration")
+	}
+
+	return &scopeRepository{db: db}, nil
+}
+
+func (s *scopeRepository) Store(ctx context.Context, scope *scope.Scope) error {
+	descriptionBytes, err := json.Marshal(scope.Descriptions)
+	if err != nil {
+		return errors.Wrap(err, "marshal descriptions")
+	}
+
+	impliesBytes, err := json.Marshal(scope.Implies)
+	if err != nil {
+		return errors.Wrap(err, "marshal implied scopes")
+	}
+
+	_, err = s.db.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(tableScope),
+		Item: map[string]*dynamodb.AttributeValue{
+			"Name":            {S: aws.String(scope.Name)},
+			"DisplayName":     {S: aws.String(scope.DisplayName)},
+			"Descriptions":    {B: descriptionBytes},
+			"RequiresConsent": {BOOL: aws.Bool(scope.RequiresConsent)},
+			"Sensitive":       {BOOL: aws.Bool(scope.Sensitive)},
+			"Implies":         {B: impliesBytes},
+			"CreatedAt":       {N: aws.String(strconv.Itoa(int(time.Now().Unix())))},
+			"UpdatedAt":       {N: aws.String(strconv.Itoa(0))},
+		},
+	})
+
+	return errors.Wrap(err, "execute query")
+}
+
+func (s *scopeRepository) Update(ctx context.Context, scope *scope.Scope) error {
+	descriptionBytes, err := json.Marshal(scope.Descriptions)
+	if err != nil {
+		return errors.Wrap(err, "marshal descriptions")
+	}
+
+	impliesBytes, err := json.Marshal(scope.Implies)
+	if err != nil {
+		return errors.Wrap(err, "marshal implied scopes")
+	}
+
+	_, err = s.db.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableScope),
+		Key: map[string]*dynamodb.AttributeValue{
+			"Name": {S: aws.String(scope.Name)},
+		},
+		UpdateExpression: aws.String("SET DisplayName = :DisplayName, Descriptions = :Descriptions, RequiresConsent = :RequiresConsent, Sensitive = :Sensitive, Implies = :Implies, UpdatedAt = :UpdatedAt"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":DisplayName":     {S: aws.String(scope.DisplayName)},
+			":Descriptions":    {B: descriptionBytes},
+			":RequiresConsent": {BOOL: aws.Bool(scope.RequiresConsent)},
+			":Sensitive":       {BOOL: aws.Bool(scope.Sensitive)},
+			":Implies":         {B: impliesBytes},
+			":UpdatedAt":       {N: aws.String(strconv.Itoa(int(time.Now().Unix())))},
+		},
+	})
+
+	return errors.Wrap(err, "execute query")
+}
+
+func (s *scopeRepository) FindByName(ctx context.Context, name string) (*scope.Scope, error) {
+	result, err := s.db.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(tableScope),
+		Key: map[string]*dynamodb.AttributeValue{
+			"Name": {S: aws.String(name)},
+		},
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "execute query")
+	}
+
+	if len(result.Item) == 0 {
+		return nil, nil
+	}
+
+	var representation scopeRepresentation
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &representation); err != nil {
+		return nil, errors.Wrap(err, "unmarshal query result")
+	}
+
+	return representation.toScope()
+}
+
+func (s *scopeRepository) FindAll(ctx context.Context) ([]*scope.Scope, error) {
+	input := &dynamodb.ScanInput{
+		TableName: aws.String(tableScope),
+	}
+
+	var representations []scopeRepresentation
+	var unmarshalErr error
+	err := s.db.ScanPagesWithContext(ctx, input, func(output *dynamodb.ScanOutput, lastPage bool) bool {
+		var page []scopeRepresentation
+		if unmarshalErr = dynamodbattribute.UnmarshalListOfMaps(output.Items, &page); unmarshalErr != nil {
+			return false
+		}
+
+		representations = append(representations, page...)
+
+		return true
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "execute query")
+	}
+
+	if unmarshalErr != nil {
+		return nil, errors.Wrap(unmarshalErr, "unmarshal query result")
+	}
+
+	scopes := make([]*scope.Scope, len(representations))
+	for i := range representations {
+		if scopes[i], err = representations[i].toScope(); err != nil {
+			return nil, errors.Wrapf(err, "convert scope %q", representations[i].Name)
+		}
+	}
+
+	return scopes, nil
+}
+
+func (s *scopeRepository) Delete(ctx context.Context, scope *scope.Scope) error {
+	_, err := s.db.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(tableScope),
+		Key: map[string]*dynamodb.AttributeValue{
+			"Name": {S: aws.String(scope.Name)},
+		},
+	})
+
+	return errors.Wrap(err, "execute query")
+}
+
+func migrateScopeTable(db *dynamodb.DynamoDB) error {
+	tables, err := db.ListTables(nil)
+	if err != nil {
+		return err
+	}
+
+	for _, table := range tables.TableNames {
+		if *table == tableScope {
+			return nil
+		}
+	}
+
+	_, err = db.CreateTable(&dynamodb.CreateTableInput{
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{AttributeName: aws.String("Name"), AttributeType: aws.String("S")},
+		},
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("Name"), KeyType: aws.String("HASH")},
+		},
+		ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(5),
+			WriteCapacityUnits: aws.Int64(10),
+		},
+		TableName: aws.String(tableScope),
+	})
+
+	return err
+}
@@ -0,0 +1,242 @@
+package persistence
+
+import (
+	"context"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/damejeras/auth/internal/session"
+	"github.com/pkg/errors"
+	"strconv"
+	"time"
+)
+
+const tableSession = "oauth2_session"
+
+type sessionRepresentation struct {
+	ID              string
+	SubjectID       string
+	ClientIDs       []string
+	UserAgent       string
+	IPAddress       string
+	AuthenticatedAt int64
+	ExpiresAt       int64
+	CreatedAt       int64
+	UpdatedAt       int64
+}
+
+func (r sessionRepresentation) toSession() *session.Session {
+	return &session.Session{
+		ID:              r.ID,
+		SubjectID:       r.SubjectID,
+		ClientIDs:       r.ClientIDs,
+		UserAgent:       r.UserAgent,
+		IPAddress:       r.IPAddress,
+		AuthenticatedAt: time.Unix(r.AuthenticatedAt, 0),
+		ExpiresAt:       time.Unix(r.ExpiresAt, 0),
+		CreatedAt:       time.Unix(r.CreatedAt, 0),
+		UpdatedAt:       time.Unix(r.UpdatedAt, 0),
+	}
+}
+
+type sessionRepository struct {
+	db *dynamodb.DynamoDB
+}
+
+func NewSessionRepository(db *dynamodb.DynamoDB) (session.Repository, error) {
+	if err := migrateSessionTable(db); err != nil {
+		return nil, errors.Wrap(err, "run table migration")
+	}
+
+	return &sessionRepository{db: db}, nil
+}
+
+func (s *sessionRepository) Store(ctx context.Context, session *session.Session) error {
+	item := map[string]*dynamodb.AttributeValue{
+		"ID":              {S: aws.String(session.ID)},
+		"SubjectID":       {S: aws.String(session.SubjectID)},
+		"UserAgent":       {S: aws.String(session.UserAgent)},
+		"IPAddress":       {S: aws.String(session.IPAddress)},
+		"AuthenticatedAt": {N: aws.String(strconv.Itoa(int(session.AuthenticatedAt.Unix())))},
+		"ExpiresAt":       {N: aws.String(strconv.Itoa(int(session.ExpiresAt.Unix())))},
+		"CreatedAt":       {N: aws.String(strconv.Itoa(int(time.Now().Unix())))},
+		"UpdatedAt":       {N: aws.String(strconv.Itoa(0))},
+	}
+
+	// string sets can not be empty
+	if len(session.ClientIDs) > 0 {
+		item["ClientIDs"] = &dynamodb.AttributeValue{SS: aws.StringSlice(session.ClientIDs)}
+	}
+
+	_, err := s.db.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(tableSession),
+		Item:      item,
+	})
+
+	return errors.Wrap(err, "execute query")
+}
+
+func (s *sessionRepository) UpdateWithAuthentication(ctx context.Context, session *session.Session) error {
+	_, err := s.db.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableSession),
+		Key: map[string]*dynamodb.AttributeValue{
+			"ID": {S: aws.String(session.ID)},
+		},
+		UpdateExpression: aws.String("SET AuthenticatedAt = :AuthenticatedAt, ExpiresAt = :ExpiresAt, UpdatedAt = :UpdatedAt"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":AuthenticatedAt": {N: aws.String(strconv.Itoa(int(session.AuthenticatedAt.Unix())))},
+			":ExpiresAt":       {N: aws.String(strconv.Itoa(int(session.ExpiresAt.Unix())))},
+			":UpdatedAt":       {N: aws.String(strconv.Itoa(int(time.Now().Unix())))},
+		},
+	})
+
+	return errors.Wrap(err, "execute query")
+}
+
+// UpdateWithClientIDs adds session client IDs to the stored set, so concurrent authorizations do not overwrite each other.
+func (s *sessionRepository) UpdateWithClientIDs(ctx context.Context, session *session.Session) error {
+	if len(session.ClientIDs) == 0 {
+		return nil
+	}
+
+	_, err := s.db.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(tableSession),
+		Key: map[string]*dynamodb.AttributeValue{
+			"ID": {S: aws.String(session.ID)},
+		},
+		UpdateExpression: aws.String("ADD ClientIDs :ClientIDs SET UpdatedAt = :UpdatedAt"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":ClientIDs": {SS: aws.StringSlice(session.ClientIDs)},
+			":UpdatedAt": {N: aws.String(strconv.Itoa(int(time.Now().Unix())))},
+		},
+	})
+
+	return errors.Wrap(err, "execute query")
+}
+
+func (s *sessionRepository) FindByID(ctx context.Context, id string) (*session.Session, error) {
+	result, err := s.db.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(tableSession),
+		Key: map[string]*dynamodb.AttributeValue{
+			"ID": {S: aws.String(id)},
+		},
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "execute query")
+	}
+
+	if len(result.Item) == 0 {
+		return nil, nil
+	}
+
+	var representation sessionRepresentation
+	if err := dynamodbattribute.UnmarshalMap(result.Item, &representation); err != nil {
+		return nil, errors.Wrap(err, "unmarshal query result")
+	}
+
+	return representation.toSession(), nil
+}
+
+func (s *sessionRepository) FindBySubject(ctx context.Context, subjectID string) ([]*session.Session, error) {
+	input := &dynamodb.QueryInput{
+		IndexName: aws.String("SessionSubjectIndex"),
+		KeyConditions: map[string]*dynamodb.Condition{
+			"SubjectID": {
+				ComparisonOperator: aws.String("EQ"),
+				AttributeValueList: []*dynamodb.AttributeValue{
+					{
+						S: aws.String(subjectID),
+					},
+				},
+			},
+		},
+		TableName: aws.String(tableSession),
+	}
+
+	var representations []sessionRepresentation
+	var unmarshalErr error
+	err := s.db.QueryPagesWithContext(ctx, input, func(output *dynamodb.QueryOutput, lastPage bool) bool {
+		var page []sessionRepresentation
+		if unmarshalErr = dynamodbattribute.UnmarshalListOfMaps(output.Items, &page); unmarshalErr != nil {
+			return false
+		}
+
+		representations = append(representations, page...)
+
+		return true
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "execute query")
+	}
+
+	if unmarshalErr != nil {
+		return nil, errors.Wrap(unmarshalErr, "unmarshal query result")
+	}
+
+	sessions := make([]*session.Session, len(representations))
+	for i := range representations {
+		sessions[i] = representations[i].toSession()
+	}
+
+	return sessions, nil
+}
+
+func (s *sessionRepository) Delete(ctx context.Context, session *session.Session) error {
+	_, err := s.db.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(tableSession),
+		Key: map[string]*dynamodb.AttributeValue{
+			"ID": {S: aws.String(session.ID)},
+		},
+	})
+
+	return errors.Wrap(err, "execute query")
+}
+
+func migrateSessionTable(db *dynamodb.DynamoDB) error {
+	subjectIndex := &dynamodb.GlobalSecondaryIndex{
+		IndexName: aws.String("SessionSubjectIndex"),
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("SubjectID"), KeyType: aws.String("HASH")},
+		},
+		Projection: &dynamodb.Projection{
+			ProjectionType: aws.String("ALL"),
+		},
+		ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(5),
+			WriteCapacityUnits: aws.Int64(10),
+		},
+	}
+
+	tables, err := db.ListTables(nil)
+	if err != nil {
+		return err
+	}
+
+	for _, table := range tables.TableNames {
+		if *table == tableSession {
+			return migrateGlobalSecondaryIndex(db, tableSession, subjectIndex, &dynamodb.AttributeDefinition{
+				AttributeName: aws.String("SubjectID"), AttributeType: aws.String("S"),
+			})
+		}
+	}
+
+	_, err = db.CreateTable(&dynamodb.CreateTableInput{
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{AttributeName: aws.String("ID"), AttributeType: aws.String("S")},
+			{AttributeName: aws.String("SubjectID"), AttributeType: aws.String("S")},
+		},
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("ID"), KeyType: aws.String("HASH")},
+		},
+		GlobalSecondaryIndexes: []*dynamodb.GlobalSecondaryIndex{subjectIndex},
+		ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+			ReadCapacityUnits:  aws.Int64(5),
+			WriteCapacityUnits: aws.Int64(10),
+		},
+		TableName: aws.String(tableSession),
+	})
+
+	return err
+}
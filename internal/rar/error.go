@@ -0,0 +1,17 @@
+package rar
+
+import (
+	stdErrors "errors"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"net/http"
+)
+
+// ErrInvalidAuthorizationDetails is authorization and token endpoint error defined by RFC 9396 section 5.
+var ErrInvalidAuthorizationDetails = stdErrors.New("invalid_authorization_details")
+
+// register error with oauth2 server, so it is rendered as regular oauth2 error.
+func init() {
+	errors.Descriptions[ErrInvalidAuthorizationDetails] = "The authorization_details parameter is malformed or contains unsupported details"
+
+	errors.StatusCodes[ErrInvalidAuthorizationDetails] = http.StatusBadRequest
+}
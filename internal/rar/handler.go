@@ -0,0 +1,96 @@
+package rar
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/damejeras/auth/internal/app"
+	"github.com/damejeras/auth/internal/consent"
+	"github.com/damejeras/auth/pkg/dynamo"
+	"github.com/go-oauth2/oauth2/v4"
+	pkgErrors "github.com/pkg/errors"
+	"net/http"
+)
+
+const (
+	// ParamAuthorizationDetails is request parameter and token claim defined by RFC 9396.
+	ParamAuthorizationDetails = "authorization_details"
+	ClaimAuthorizationDetails = "authorization_details"
+)
+
+// Handler resolves rich authorization requests defined by RFC 9396.
+type Handler struct {
+	consentRepository consent.Repository
+}
+
+func NewHandler(consentRepository consent.Repository) *Handler {
+	return &Handler{consentRepository: consentRepository}
+}
+
+// AuthorizeMiddleware validates authorization details at authorization endpoint and binds them to issued authorization code.
+// Login and consent are handled by identity manager, which reads details from request parameters.
+func (h *Handler) AuthorizeMiddleware(next func(w http.ResponseWriter, r *http.Request) error) func(w http.ResponseWriter, r *http.Request) error {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		details, err := consent.ParseAuthorizationDetails(r.URL.Query().Get(ParamAuthorizationDetails))
+		if err != nil {
+			return app.WriteError(w, ErrInvalidAuthorizationDetails)
+		}
+
+		if len(details) == 0 {
+			return next(w, r)
+		}
+
+		return next(w, r.WithContext(dynamo.WithExtension(r.Context(), ClaimAuthorizationDetails, details)))
+	}
+}
+
+// ApprovedDetails returns authorization details of authorization code, which subject has consented to.
+// Consent provider may approve only some of requested details, the rest are not passed to the token.
+// Details of expired consent are not approved anymore, details granted once are approved until next grant.
+func (h *Handler) ApprovedDetails(ctx context.Context, code oauth2.TokenInfo) (consent.AuthorizationDetails, error) {
+	token, ok := code.(*dynamo.Token)
+	if !ok {
+		return nil, nil
+	}
+
+	details, err := Details(token)
+	if err != nil || len(details) == 0 {
+		return nil, err
+	}
+
+	cs, err := h.consentRepository.FindByClientAndSubject(ctx, token.GetClientID(), token.GetUserID())
+	if err != nil {
+		return nil, pkgErrors.Wrapf(err, "find client's %q consent for subject %q", token.GetClientID(), token.GetUserID())
+	}
+
+	if cs == nil {
+		return make(consent.AuthorizationDetails, 0), nil
+	}
+
+	approved := cs.OnceAuthorizationDetails
+	if !cs.Expired() {
+		approved = approved.Merge(cs.AuthorizationDetails)
+	}
+
+	return details.Intersect(approved), nil
+}
+
+// Details returns authorization details token was issued for. Claim loaded from token store is decoded JSON,
+// so it is converted to authorization details the same way consent provider's details are.
+func Details(token *dynamo.Token) (consent.AuthorizationDetails, error) {
+	claim := token.GetExtension(ClaimAuthorizationDetails)
+	if claim == nil {
+		return nil, nil
+	}
+
+	claimBytes, err := json.Marshal(claim)
+	if err != nil {
+		return nil, pkgErrors.Wrap(err, "marshal authorization details claim")
+	}
+
+	var details consent.AuthorizationDetails
+	if err := json.Unmarshal(claimBytes, &details); err != nil {
+		return nil, pkgErrors.Wrap(err, "unmarshal authorization details claim")
+	}
+
+	return details, nil
+}
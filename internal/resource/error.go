@@ -0,0 +1,16 @@
+package resource
+
+import (
+	stdErrors "errors"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"net/http"
+)
+
+// ErrInvalidTarget is returned when requested resource is unknown or token can not be issued for it, RFC 8707 section 2.
+var ErrInvalidTarget = stdErrors.New("invalid_target")
+
+// register errors with oauth2 server, so they are rendered as regular token endpoint errors.
+func init() {
+	errors.Descriptions[ErrInvalidTarget] = "The authorization server is unwilling or unable to issue a token for the indicated target service"
+	errors.StatusCodes[ErrInvalidTarget] = http.StatusBadRequest
+}
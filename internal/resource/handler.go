@@ -0,0 +1,46 @@
+package resource
+
+import (
+	"github.com/damejeras/auth/internal/app"
+	"github.com/damejeras/auth/internal/client"
+	"github.com/damejeras/auth/pkg/dynamo"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	pkgErrors "github.com/pkg/errors"
+	"net/http"
+)
+
+// Handler validates resource indicators at authorization endpoint.
+type Handler struct {
+	storage       *Storage
+	clientStorage *client.Storage
+}
+
+func NewHandler(storage *Storage, clientStorage *client.Storage) *Handler {
+	return &Handler{
+		storage:       storage,
+		clientStorage: clientStorage,
+	}
+}
+
+// AuthorizeMiddleware rejects unknown resources and binds requested ones to issued authorization code,
+// so tokens exchanged for the code can be restricted to them.
+func (h *Handler) AuthorizeMiddleware(next func(w http.ResponseWriter, r *http.Request) error) func(w http.ResponseWriter, r *http.Request) error {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		uris := r.URL.Query()[ParamResource]
+		if len(uris) == 0 {
+			return next(w, r)
+		}
+
+		if _, err := h.storage.FindAll(r.Context(), uris); err != nil {
+			if err == ErrInvalidTarget {
+				return h.clientStorage.WriteAuthorizeError(w, r, err)
+			}
+
+			app.WriteError(w, errors.ErrServerError)
+
+			return pkgErrors.Wrap(err, "find requested resources")
+		}
+
+		return next(w, r.WithContext(dynamo.WithExtension(r.Context(), ClaimAudience, uris)))
+	}
+}
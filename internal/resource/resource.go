@@ -0,0 +1,85 @@
+package resource
+
+import (
+	"github.com/damejeras/auth/internal/consent"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	"strings"
+)
+
+const (
+	// ParamResource is resource indicator parameter defined by RFC 8707.
+	ParamResource = "resource"
+	// ClaimAudience is token claim holding URIs of resources token is restricted to.
+	ClaimAudience = "aud"
+)
+
+// Resource is protected resource, which accepts tokens restricted to it and carrying its scopes only.
+type Resource struct {
+	URI    string
+	Scopes consent.Scopes
+}
+
+// RestrictScope drops requested scopes, which are not accepted by any of the resources.
+// Empty scope stays empty, but scope having none of the accepted scopes is invalid.
+func RestrictScope(scope string, resources []*Resource) (string, error) {
+	requested := strings.Fields(scope)
+	if len(requested) == 0 {
+		return "", nil
+	}
+
+	restricted := make([]string, 0, len(requested))
+	for i := range requested {
+		for j := range resources {
+			if _, ok := resources[j].Scopes[requested[i]]; ok {
+				restricted = append(restricted, requested[i])
+
+				break
+			}
+		}
+	}
+
+	if len(restricted) == 0 {
+		return "", errors.ErrInvalidScope
+	}
+
+	return strings.Join(restricted, " "), nil
+}
+
+// Audience returns URIs of resources token is restricted to. Claim loaded from token store is slice of interfaces.
+func Audience(extension map[string]interface{}) []string {
+	switch audience := extension[ClaimAudience].(type) {
+	case []string:
+		return audience
+	case []interface{}:
+		result := make([]string, 0, len(audience))
+		for i := range audience {
+			if uri, ok := audience[i].(string); ok {
+				result = append(result, uri)
+			}
+		}
+
+		return result
+	default:
+		return nil
+	}
+}
+
+// IsSubset reports whether every requested URI is one of the authorized URIs.
+func IsSubset(requested, authorized []string) bool {
+	for i := range requested {
+		found := false
+		for j := range authorized {
+			if requested[i] == authorized[j] {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
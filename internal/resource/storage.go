@@ -0,0 +1,57 @@
+package resource
+
+import (
+	"context"
+	"github.com/damejeras/auth/internal/app"
+	"github.com/damejeras/auth/internal/consent"
+	"github.com/pkg/errors"
+	"net/url"
+)
+
+type Storage struct {
+	resources map[string]*Resource
+}
+
+func NewStorage(cfg *app.Config) (*Storage, error) {
+	storage := Storage{
+		resources: make(map[string]*Resource),
+	}
+
+	for _, resource := range cfg.ResourceConfig.Resources {
+		// resource indicator must be absolute URI without fragment, RFC 8707 section 2
+		uri, err := url.Parse(resource.URI)
+		if err != nil || !uri.IsAbs() || uri.Fragment != "" {
+			return nil, errors.Errorf("resource URI %q must be absolute and must not have fragment", resource.URI)
+		}
+
+		storage.resources[resource.URI] = &Resource{
+			URI:    resource.URI,
+			Scopes: consent.BuildScopes(resource.Scopes),
+		}
+	}
+
+	return &storage, nil
+}
+
+func (s *Storage) FindByID(_ context.Context, uri string) (*Resource, error) {
+	return s.resources[uri], nil
+}
+
+// FindAll returns resources for all given URIs or ErrInvalidTarget if any of them is not registered.
+func (s *Storage) FindAll(ctx context.Context, uris []string) ([]*Resource, error) {
+	resources := make([]*Resource, 0, len(uris))
+	for i := range uris {
+		resource, err := s.FindByID(ctx, uris[i])
+		if err != nil {
+			return nil, errors.Wrapf(err, "find resource %q", uris[i])
+		}
+
+		if resource == nil {
+			return nil, ErrInvalidTarget
+		}
+
+		resources = append(resources, resource)
+	}
+
+	return resources, nil
+}
@@ -0,0 +1,34 @@
+package scope
+
+import (
+	"github.com/damejeras/auth/internal/app"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	pkgErrors "github.com/pkg/errors"
+	"net/http"
+	"strings"
+)
+
+// Handler rejects authorization requests for scopes, which are not registered.
+type Handler struct {
+	registry *Registry
+}
+
+func NewHandler(registry *Registry) *Handler {
+	return &Handler{registry: registry}
+}
+
+func (h *Handler) AuthorizeMiddleware(next func(w http.ResponseWriter, r *http.Request) error) func(w http.ResponseWriter, r *http.Request) error {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		_, err := h.registry.Resolve(r.Context(), strings.Fields(r.URL.Query().Get("scope")))
+		switch {
+		case err == errors.ErrInvalidScope:
+			return app.WriteError(w, err)
+		case err != nil:
+			app.WriteError(w, errors.ErrServerError)
+
+			return pkgErrors.Wrap(err, "resolve requested scopes")
+		}
+
+		return next(w, r)
+	}
+}
@@ -0,0 +1,68 @@
+package scope
+
+import "strings"
+
+// scope names are split into segments, segment in braces is placeholder matching any value, e.g. account:{id}:read.
+const segmentSeparator = ":"
+
+// matchPattern reports whether value matches pattern and returns values of pattern placeholders.
+// Value with placeholder segment never matches, otherwise it would stand for every value of the pattern.
+func matchPattern(pattern, value string) (map[string]string, bool) {
+	if isPattern(value) {
+		return nil, false
+	}
+
+	patternSegments := strings.Split(pattern, segmentSeparator)
+	valueSegments := strings.Split(value, segmentSeparator)
+
+	if len(patternSegments) != len(valueSegments) {
+		return nil, false
+	}
+
+	parameters := make(map[string]string)
+	for i := range patternSegments {
+		if name, ok := placeholder(patternSegments[i]); ok && valueSegments[i] != "" {
+			parameters[name] = valueSegments[i]
+
+			continue
+		}
+
+		if patternSegments[i] != valueSegments[i] {
+			return nil, false
+		}
+	}
+
+	return parameters, true
+}
+
+// substitute replaces pattern placeholders with given values, placeholders without value are left as they are.
+func substitute(pattern string, parameters map[string]string) string {
+	segments := strings.Split(pattern, segmentSeparator)
+	for i := range segments {
+		if name, ok := placeholder(segments[i]); ok {
+			if value, ok := parameters[name]; ok {
+				segments[i] = value
+			}
+		}
+	}
+
+	return strings.Join(segments, segmentSeparator)
+}
+
+func placeholder(segment string) (string, bool) {
+	if len(segment) < 3 || segment[0] != '{' || segment[len(segment)-1] != '}' {
+		return "", false
+	}
+
+	return segment[1 : len(segment)-1], true
+}
+
+func isPattern(name string) bool {
+	for _, segment := range strings.Split(name, segmentSeparator) {
+		if _, ok := placeholder(segment); ok {
+			return true
+		}
+	}
+
+	return false
+}
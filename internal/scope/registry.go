@@ -0,0 +1,222 @@
+package scope
+
+import (
+	"context"
+	"github.com/damejeras/auth/internal/app"
+	"github.com/go-oauth2/oauth2/v4/errors"
+	pkgErrors "github.com/pkg/errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// catalogVersion is incremented by admin API writes, so every registry in the process reloads its catalog.
+var catalogVersion uint64
+
+// invalidateCatalogs makes registries reload registered scopes on their next call.
+func invalidateCatalogs() {
+	atomic.AddUint64(&catalogVersion, 1)
+}
+
+// Match is registered scope requested scope value matches. Parameters hold values of placeholders
+// of parameterized scope, e.g. id of account:{id}:read.
+type Match struct {
+	Value      string
+	Scope      *Scope
+	Parameters map[string]string
+}
+
+// Registry resolves requested scopes to registered ones and tells whether consented scopes imply requested ones.
+// Registered scopes are cached, as they are read several times by every authorization request.
+type Registry struct {
+	repository Repository
+	lifetime   time.Duration
+
+	mu        sync.Mutex
+	catalog   *catalog
+	version   uint64
+	expiresAt time.Time
+}
+
+func NewRegistry(repository Repository, cfg *app.Config) *Registry {
+	return &Registry{
+		repository: repository,
+		lifetime:   cfg.ScopeConfig.CatalogLifetime,
+	}
+}
+
+// Resolve returns registered scopes in the order they were requested, unknown scope results in invalid scope error.
+func (r *Registry) Resolve(ctx context.Context, names []string) ([]*Match, error) {
+	c, err := r.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]*Match, 0, len(names))
+	for i := range names {
+		match := c.match(names[i])
+		if match == nil {
+			return nil, errors.ErrInvalidScope
+		}
+
+		matches = append(matches, match)
+	}
+
+	return matches, nil
+}
+
+// RequiringConsent returns scopes subject has to consent to explicitly. Scopes, which are not registered,
+// are not expected at this point, but they are treated as requiring consent.
+func (r *Registry) RequiringConsent(ctx context.Context, names []string) ([]string, error) {
+	c, err := r.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(names))
+	for i := range names {
+		if match := c.match(names[i]); match == nil || match.Scope.RequiresConsent {
+			result = append(result, names[i])
+		}
+	}
+
+	return result, nil
+}
+
+// Unsatisfied returns requested scopes, which are neither granted nor implied by granted ones.
+func (r *Registry) Unsatisfied(ctx context.Context, granted, requested []string) ([]string, error) {
+	c, err := r.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	implied := make(map[string]struct{})
+	for i := range granted {
+		// placeholders can not be requested, so granted value with placeholder must not act as wildcard
+		if isPattern(granted[i]) {
+			continue
+		}
+
+		c.expand(granted[i], implied)
+	}
+
+	result := make([]string, 0, len(requested))
+	for i := range requested {
+		if !satisfies(implied, requested[i]) {
+			result = append(result, requested[i])
+		}
+	}
+
+	return result, nil
+}
+
+// load returns cached catalog, catalog is reloaded when it expires or scopes are changed through admin API.
+func (r *Registry) load(ctx context.Context) (*catalog, error) {
+	version := atomic.LoadUint64(&catalogVersion)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.catalog != nil && r.version == version && time.Now().Before(r.expiresAt) {
+		return r.catalog, nil
+	}
+
+	scopes, err := r.repository.FindAll(ctx)
+	if err != nil {
+		return nil, pkgErrors.Wrap(err, "find scopes")
+	}
+
+	c := catalog{
+		exact: make(map[string]*Scope, len(scopes)),
+	}
+
+	for i := range scopes {
+		if isPattern(scopes[i].Name) {
+			c.patterns = append(c.patterns, scopes[i])
+		} else {
+			c.exact[scopes[i].Name] = scopes[i]
+		}
+	}
+
+	r.catalog = &c
+	r.version = version
+	r.expiresAt = time.Now().Add(r.lifetime)
+
+	return r.catalog, nil
+}
+
+// catalog is snapshot of registered scopes, it is not modified once loaded.
+type catalog struct {
+	exact    map[string]*Scope
+	patterns []*Scope
+}
+
+// match finds registered scope for the value, exactly registered scopes take precedence over parameterized ones.
+func (c *catalog) match(value string) *Match {
+	if scope, ok := c.exact[value]; ok {
+		return &Match{Value: value, Scope: scope, Parameters: make(map[string]string)}
+	}
+
+	for i := range c.patterns {
+		if parameters, ok := matchPattern(c.patterns[i].Name, value); ok {
+			return &Match{Value: value, Scope: c.patterns[i], Parameters: parameters}
+		}
+	}
+
+	return nil
+}
+
+// pattern finds parameterized scope registered under the name. Implied scopes can keep placeholders of implying scope,
+// they do not match any pattern, but imply scopes of the pattern they are registered as.
+func (c *catalog) pattern(name string) *Match {
+	for i := range c.patterns {
+		if c.patterns[i].Name == name {
+			return &Match{Value: name, Scope: c.patterns[i], Parameters: make(map[string]string)}
+		}
+	}
+
+	return nil
+}
+
+// expand adds value and all scopes it implies to the set. Implied scopes inherit parameters of implying scope,
+// so account:{id} granted as account:1 implies account:1:read only.
+func (c *catalog) expand(value string, implied map[string]struct{}) {
+	if _, ok := implied[value]; ok {
+		return
+	}
+
+	implied[value] = struct{}{}
+
+	match := c.match(value)
+	if match == nil {
+		match = c.pattern(value)
+	}
+
+	if match == nil {
+		return
+	}
+
+	for i := range match.Scope.Implies {
+		c.expand(substitute(match.Scope.Implies[i], match.Parameters), implied)
+	}
+}
+
+// satisfies reports whether one of implied scopes is requested value or pattern matching it.
+// Requested value with placeholder is never satisfied.
+func satisfies(implied map[string]struct{}, value string) bool {
+	if isPattern(value) {
+		return false
+	}
+
+	if _, ok := implied[value]; ok {
+		return true
+	}
+
+	for scope := range implied {
+		if _, ok := matchPattern(scope, value); ok && isPattern(scope) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,49 @@
+package scope
+
+import (
+	"context"
+	"time"
+)
+
+// Scope is registered scope clients are allowed to request. Parameterized scope has placeholders in its name,
+// e.g. account:{id}:read, and is requested with concrete values, e.g. account:1:read.
+type Scope struct {
+	Name        string
+	DisplayName string
+	// Descriptions are shown to the subject by consent provider, they are keyed by BCP 47 language tag.
+	Descriptions map[string]string
+	// RequiresConsent makes subject explicitly consent to the scope, other scopes are granted implicitly.
+	RequiresConsent bool
+	// Sensitive scopes grant access to sensitive data, consent provider is expected to highlight them.
+	Sensitive bool
+	// Implies are narrower scopes consent to this scope covers, e.g. files implies files:read.
+	// Placeholders of parameterized scope are passed to implied scopes with the same placeholders.
+	Implies []string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type Repository interface {
+	Store(ctx context.Context, scope *Scope) error
+	Update(ctx context.Context, scope *Scope) error
+	FindByName(ctx context.Context, name string) (*Scope, error)
+	FindAll(ctx context.Context) ([]*Scope, error)
+	Delete(ctx context.Context, scope *Scope) error
+}
+
+// ValidName reports whether name is valid scope token as defined by RFC 6749 section 3.3.
+func ValidName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c < 0x21 || c > 0x7E || c == '"' || c == '\\' {
+			return false
+		}
+	}
+
+	return true
+}
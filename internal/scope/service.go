@@ -0,0 +1,171 @@
+package scope
+
+import (
+	"context"
+	"github.com/damejeras/auth/api"
+	"github.com/pkg/errors"
+	"sort"
+)
+
+type service struct {
+	repository Repository
+}
+
+func NewService(repository Repository) api.ScopeService {
+	return &service{repository: repository}
+}
+
+func (s *service) ListScopes(ctx context.Context, _ api.ListScopesRequest) (*api.ListScopesResponse, error) {
+	scopes, err := s.repository.FindAll(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "find scopes")
+	}
+
+	sort.Slice(scopes, func(i, j int) bool {
+		return scopes[i].Name < scopes[j].Name
+	})
+
+	response := api.ListScopesResponse{
+		Scopes: make([]api.Scope, len(scopes)),
+	}
+
+	for i := range scopes {
+		response.Scopes[i] = ToAPI(scopes[i])
+	}
+
+	return &response, nil
+}
+
+func (s *service) GetScope(ctx context.Context, request api.GetScopeRequest) (*api.GetScopeResponse, error) {
+	scope, err := s.find(ctx, request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &api.GetScopeResponse{
+		Scope: ToAPI(scope),
+	}, nil
+}
+
+// PutScope registers new scope or replaces metadata of already registered one.
+func (s *service) PutScope(ctx context.Context, request api.PutScopeRequest) (*api.PutScopeResponse, error) {
+	if !ValidName(request.Scope.Name) {
+		return nil, errors.Errorf("invalid scope name %q", request.Scope.Name)
+	}
+
+	for i := range request.Scope.Implies {
+		if !ValidName(request.Scope.Implies[i]) {
+			return nil, errors.Errorf("invalid implied scope name %q", request.Scope.Implies[i])
+		}
+	}
+
+	scope := Scope{
+		Name:            request.Scope.Name,
+		DisplayName:     request.Scope.DisplayName,
+		Descriptions:    make(map[string]string, len(request.Scope.Descriptions)),
+		RequiresConsent: request.Scope.RequiresConsent,
+		Sensitive:       request.Scope.Sensitive,
+		Implies:         request.Scope.Implies,
+	}
+
+	for i := range request.Scope.Descriptions {
+		scope.Descriptions[request.Scope.Descriptions[i].Locale] = request.Scope.Descriptions[i].Description
+	}
+
+	existing, err := s.repository.FindByName(ctx, scope.Name)
+	if err != nil {
+		return nil, errors.Wrap(err, "find scope")
+	}
+
+	if existing != nil {
+		err = s.repository.Update(ctx, &scope)
+	} else {
+		err = s.repository.Store(ctx, &scope)
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "store scope")
+	}
+
+	invalidateCatalogs()
+
+	return &api.PutScopeResponse{
+		Scope: ToAPI(&scope),
+	}, nil
+}
+
+func (s *service) DeleteScope(ctx context.Context, request api.DeleteScopeRequest) (*api.DeleteScopeResponse, error) {
+	scope, err := s.find(ctx, request.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repository.Delete(ctx, scope); err != nil {
+		return nil, errors.Wrap(err, "delete scope")
+	}
+
+	invalidateCatalogs()
+
+	return &api.DeleteScopeResponse{}, nil
+}
+
+func (s *service) find(ctx context.Context, name string) (*Scope, error) {
+	scope, err := s.repository.FindByName(ctx, name)
+	if err != nil {
+		return nil, errors.Wrap(err, "find scope")
+	}
+
+	if scope == nil {
+		return nil, errors.New("scope not found")
+	}
+
+	return scope, nil
+}
+
+// ToAPI converts scope to its admin API representation with descriptions ordered by locale.
+func ToAPI(scope *Scope) api.Scope {
+	result := api.Scope{
+		Name:            scope.Name,
+		DisplayName:     scope.DisplayName,
+		Descriptions:    make([]api.ScopeDescription, 0, len(scope.Descriptions)),
+		RequiresConsent: scope.RequiresConsent,
+		Sensitive:       scope.Sensitive,
+		Implies:         scope.Implies,
+	}
+
+	for locale, description := range scope.Descriptions {
+		result.Descriptions = append(result.Descriptions, api.ScopeDescription{
+			Locale:      locale,
+			Description: description,
+		})
+	}
+
+	sort.Slice(result.Descriptions, func(i, j int) bool {
+		return result.Descriptions[i].Locale < result.Descriptions[j].Locale
+	})
+
+	return result
+}
+
+// RequestedToAPI converts requested scope to its admin API representation, which shows consent provider
+// concrete values of parameterized scope.
+func RequestedToAPI(match *Match) api.RequestedScope {
+	result := api.RequestedScope{
+		Value:      match.Value,
+		Parameters: make([]api.ScopeParameter, 0, len(match.Parameters)),
+		Scope:      ToAPI(match.Scope),
+	}
+
+	for name, value := range match.Parameters {
+		result.Parameters = append(result.Parameters, api.ScopeParameter{
+			Name:  name,
+			Value: value,
+		})
+	}
+
+	sort.Slice(result.Parameters, func(i, j int) bool {
+		return result.Parameters[i].Name < result.Parameters[j].Name
+	})
+
+	return result
+}
@@ -0,0 +1,48 @@
+package session
+
+import (
+	"context"
+	"github.com/damejeras/auth/pkg/dynamo"
+	"net/http"
+)
+
+type bindingContextKey struct{}
+
+// binding holds ID of the session subject is authorized in. It is not known until identity manager
+// authorizes the subject, which happens after oauth2 server captures request context.
+type binding struct {
+	sessionID string
+}
+
+// AuthorizeMiddleware binds authorization code to the session subject is authorized in, so tokens issued for the code
+// can be revoked along with the session.
+func AuthorizeMiddleware(next func(w http.ResponseWriter, r *http.Request) error) func(w http.ResponseWriter, r *http.Request) error {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		b := &binding{}
+
+		ctx := context.WithValue(r.Context(), bindingContextKey{}, b)
+		ctx = dynamo.WithExtension(ctx, dynamo.ClaimSessionID, dynamo.ExtensionFunc(func() interface{} {
+			if b.sessionID == "" {
+				return nil
+			}
+
+			return b.sessionID
+		}))
+
+		return next(w, r.WithContext(ctx))
+	}
+}
+
+// Bind records session authorization request is served in.
+func Bind(r *http.Request, session *Session) {
+	if b, ok := r.Context().Value(bindingContextKey{}).(*binding); ok {
+		b.sessionID = session.ID
+	}
+}
+
+// ID returns ID of the session token was issued in, or empty string if it was issued outside of session.
+func ID(token *dynamo.Token) string {
+	sessionID, _ := token.GetExtension(dynamo.ClaimSessionID).(string)
+
+	return sessionID
+}
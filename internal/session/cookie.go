@@ -0,0 +1,106 @@
+package session
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"github.com/damejeras/auth/internal/app"
+	"github.com/pkg/errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const cookieSession = "s"
+
+// Cookie writes and reads session ID signed with configured secret, so it can not be forged by user agent.
+type Cookie struct {
+	secret []byte
+}
+
+func NewCookie(cfg *app.Config) *Cookie {
+	return &Cookie{
+		secret: []byte(cfg.SessionConfig.Secret),
+	}
+}
+
+func (c *Cookie) Write(w http.ResponseWriter, session *Session) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     cookieSession,
+		Value:    session.ID + "." + c.sign(session.ID),
+		Path:     "/",
+		Expires:  session.ExpiresAt,
+		Secure:   false, // TODO: use true in production
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// Read returns session ID from request cookie or empty string if cookie is missing or its signature is invalid.
+func (c *Cookie) Read(r *http.Request) string {
+	cookie, err := r.Cookie(cookieSession)
+	if err != nil {
+		return ""
+	}
+
+	parts := strings.SplitN(cookie.Value, ".", 2)
+	if len(parts) != 2 {
+		return ""
+	}
+
+	if !hmac.Equal([]byte(parts[1]), []byte(c.sign(parts[0]))) {
+		return ""
+	}
+
+	return parts[0]
+}
+
+func (c *Cookie) Clear(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     cookieSession,
+		Value:    "",
+		Path:     "/",
+		Expires:  time.Unix(0, 0),
+		MaxAge:   -1,
+		Secure:   false, // TODO: use true in production
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// CSRFToken returns token bound to the session. Forms served to session owner carry it, so they can not be
+// submitted from other sites.
+func (c *Cookie) CSRFToken(session *Session) string {
+	return c.sign("csrf." + session.ID)
+}
+
+// VerifyCSRFToken reports whether token was issued for the session.
+func (c *Cookie) VerifyCSRFToken(session *Session, token string) bool {
+	return hmac.Equal([]byte(token), []byte(c.CSRFToken(session)))
+}
+
+func (c *Cookie) sign(value string) string {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(value))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// FromRequest returns valid session referenced by request cookie or nil if there is none.
+func FromRequest(r *http.Request, cookie *Cookie, repository Repository) (*Session, error) {
+	sessionID := cookie.Read(r)
+	if sessionID == "" {
+		return nil, nil
+	}
+
+	session, err := repository.FindByID(r.Context(), sessionID)
+	if err != nil {
+		return nil, errors.Wrap(err, "find session")
+	}
+
+	if session == nil || session.Expired() {
+		return nil, nil
+	}
+
+	return session, nil
+}
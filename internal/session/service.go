@@ -0,0 +1,122 @@
+package session
+
+import (
+	"context"
+	"github.com/damejeras/auth/api"
+	"github.com/damejeras/auth/pkg/dynamo"
+	"github.com/pkg/errors"
+)
+
+type service struct {
+	sessionRepository Repository
+	tokenStore        dynamo.TokenStore
+	notifier          Notifier
+}
+
+func NewService(sessionRepository Repository, tokenStore dynamo.TokenStore, notifier Notifier) api.SessionService {
+	return &service{
+		sessionRepository: sessionRepository,
+		tokenStore:        tokenStore,
+		notifier:          notifier,
+	}
+}
+
+func (s *service) ListSessionsBySubject(ctx context.Context, request api.ListSessionsBySubjectRequest) (*api.ListSessionsBySubjectResponse, error) {
+	sessions, err := s.sessionRepository.FindBySubject(ctx, request.SubjectID)
+	if err != nil {
+		return nil, errors.Wrap(err, "find sessions by subject")
+	}
+
+	response := api.ListSessionsBySubjectResponse{
+		Sessions: make([]api.Session, 0, len(sessions)),
+	}
+
+	for i := range sessions {
+		if sessions[i].Expired() {
+			continue
+		}
+
+		response.Sessions = append(response.Sessions, sessionToAPI(sessions[i]))
+	}
+
+	return &response, nil
+}
+
+func (s *service) GetSession(ctx context.Context, request api.GetSessionRequest) (*api.GetSessionResponse, error) {
+	session, err := s.sessionRepository.FindByID(ctx, request.SessionID)
+	if err != nil {
+		return nil, errors.Wrap(err, "find session")
+	}
+
+	if session == nil {
+		return nil, errors.New("session not found")
+	}
+
+	return &api.GetSessionResponse{
+		Session: sessionToAPI(session),
+	}, nil
+}
+
+func (s *service) RevokeSession(ctx context.Context, request api.RevokeSessionRequest) (*api.RevokeSessionResponse, error) {
+	session, err := s.sessionRepository.FindByID(ctx, request.SessionID)
+	if err != nil {
+		return nil, errors.Wrap(err, "find session")
+	}
+
+	if session == nil {
+		return nil, errors.New("session not found")
+	}
+
+	if err := s.revoke(ctx, session, request.RevokeTokens); err != nil {
+		return nil, err
+	}
+
+	return &api.RevokeSessionResponse{}, nil
+}
+
+func (s *service) RevokeAllSessionsForSubject(ctx context.Context, request api.RevokeAllSessionsForSubjectRequest) (*api.RevokeAllSessionsForSubjectResponse, error) {
+	sessions, err := s.sessionRepository.FindBySubject(ctx, request.SubjectID)
+	if err != nil {
+		return nil, errors.Wrap(err, "find sessions by subject")
+	}
+
+	for i := range sessions {
+		if err := s.revoke(ctx, sessions[i], request.RevokeTokens); err != nil {
+			return nil, err
+		}
+	}
+
+	return &api.RevokeAllSessionsForSubjectResponse{
+		RevokedSessions: len(sessions),
+	}, nil
+}
+
+// revoke deletes session and, if requested, tokens issued within the session.
+func (s *service) revoke(ctx context.Context, session *Session, revokeTokens bool) error {
+	if err := s.sessionRepository.Delete(ctx, session); err != nil {
+		return errors.Wrapf(err, "delete session %q", session.ID)
+	}
+
+	if err := s.notifier.NotifyLogout(ctx, session); err != nil {
+		return errors.Wrapf(err, "notify clients about session %q logout", session.ID)
+	}
+
+	if !revokeTokens {
+		return nil
+	}
+
+	return errors.Wrapf(s.tokenStore.RemoveBySession(ctx, session.ID), "remove session %q tokens", session.ID)
+}
+
+func sessionToAPI(session *Session) api.Session {
+	return api.Session{
+		SessionID:       session.ID,
+		SubjectID:       session.SubjectID,
+		ClientIDs:       session.ClientIDs,
+		UserAgent:       session.UserAgent,
+		IPAddress:       session.IPAddress,
+		AuthenticatedAt: session.AuthenticatedAt.Unix(),
+		ExpiresAt:       session.ExpiresAt.Unix(),
+		CreatedAt:       session.CreatedAt.Unix(),
+	}
+}
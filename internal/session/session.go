@@ -0,0 +1,53 @@
+package session
+
+import (
+	"context"
+	"time"
+)
+
+type Session struct {
+	ID              string
+	SubjectID       string
+	ClientIDs       []string
+	UserAgent       string
+	IPAddress       string
+	AuthenticatedAt time.Time
+	ExpiresAt       time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (s *Session) Expired() bool {
+	return time.Now().After(s.ExpiresAt)
+}
+
+// AuthenticatedWithin reports whether subject authenticated not earlier than maxAge ago.
+func (s *Session) AuthenticatedWithin(maxAge time.Duration) bool {
+	return time.Since(s.AuthenticatedAt) <= maxAge
+}
+
+// HasClient reports whether client was authorized during this session.
+func (s *Session) HasClient(clientID string) bool {
+	for i := range s.ClientIDs {
+		if s.ClientIDs[i] == clientID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Notifier informs clients authorized during the session that session has ended.
+type Notifier interface {
+	NotifyLogout(context.Context, *Session) error
+}
+
+type Repository interface {
+	Store(context.Context, *Session) error
+	UpdateWithAuthentication(context.Context, *Session) error
+	UpdateWithClientIDs(context.Context, *Session) error
+	FindByID(context.Context, string) (*Session, error)
+	FindBySubject(context.Context, string) ([]*Session, error)
+	Delete(context.Context, *Session) error
+}
@@ -0,0 +1,111 @@
+package signing
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"hash"
+	"strings"
+)
+
+// key management algorithms and content encryptions supported for JSON Web Encryption, RFC 7516.
+const (
+	algRSAOAEP    = "RSA-OAEP"
+	algRSAOAEP256 = "RSA-OAEP-256"
+)
+
+var contentKeySizes = map[string]int{
+	"A128GCM": 16,
+	"A192GCM": 24,
+	"A256GCM": 32,
+}
+
+type encryptionHeader struct {
+	Alg string `json:"alg"`
+	Enc string `json:"enc"`
+	Kid string `json:"kid,omitempty"`
+}
+
+// IsEncrypted reports whether token is in JWE compact serialization, which has five parts, unlike JWS having three.
+func IsEncrypted(token string) bool {
+	return strings.Count(token, ".") == 4
+}
+
+// Decrypt decrypts token in JWE compact serialization, which was encrypted for this server's public key.
+func (s *Signer) Decrypt(token string) ([]byte, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 5 {
+		return nil, errors.New("token is not in compact serialization")
+	}
+
+	headerBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, errors.Wrap(err, "decode header")
+	}
+
+	var header encryptionHeader
+	if err := json.Unmarshal(headerBytes, &header); err != nil {
+		return nil, errors.Wrap(err, "unmarshal header")
+	}
+
+	if header.Kid != "" && header.Kid != s.keyID {
+		return nil, errors.Errorf("unknown key %q", header.Kid)
+	}
+
+	var oaepHash hash.Hash
+	switch header.Alg {
+	case algRSAOAEP:
+		oaepHash = sha1.New()
+	case algRSAOAEP256:
+		oaepHash = sha256.New()
+	default:
+		return nil, errors.Errorf("unsupported key management algorithm %q", header.Alg)
+	}
+
+	keySize, ok := contentKeySizes[header.Enc]
+	if !ok {
+		return nil, errors.Errorf("unsupported content encryption %q", header.Enc)
+	}
+
+	decoded := make([][]byte, 4)
+	for i := range decoded {
+		if decoded[i], err = base64.RawURLEncoding.DecodeString(parts[i+1]); err != nil {
+			return nil, errors.Wrapf(err, "decode part %d", i+2)
+		}
+	}
+
+	encryptedKey, iv, ciphertext, tag := decoded[0], decoded[1], decoded[2], decoded[3]
+
+	contentKey, err := rsa.DecryptOAEP(oaepHash, rand.Reader, s.key, encryptedKey, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypt content key")
+	}
+
+	if len(contentKey) != keySize {
+		return nil, errors.New("content key size does not match content encryption")
+	}
+
+	block, err := aes.NewCipher(contentKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "create cipher")
+	}
+
+	gcm, err := cipher.NewGCMWithNonceSize(block, len(iv))
+	if err != nil {
+		return nil, errors.Wrap(err, "create gcm")
+	}
+
+	// additional authenticated data is encoded protected header, RFC 7516 section 5.2
+	plaintext, err := gcm.Open(nil, iv, append(ciphertext, tag...), []byte(parts[0]))
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypt content")
+	}
+
+	return plaintext, nil
+}
@@ -0,0 +1,138 @@
+package signing
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"math/big"
+)
+
+// JSONWebKey is public key in JSON Web Key format defined by RFC 7517.
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// PublicKey converts key to *rsa.PublicKey or *ecdsa.PublicKey, which can be used to verify token signature.
+func (k *JSONWebKey) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeKeyParameter(k.N)
+		if err != nil {
+			return nil, errors.Wrap(err, "decode modulus")
+		}
+
+		e, err := decodeKeyParameter(k.E)
+		if err != nil {
+			return nil, errors.Wrap(err, "decode exponent")
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, errors.Errorf("unsupported curve %q", k.Crv)
+		}
+
+		x, err := decodeKeyParameter(k.X)
+		if err != nil {
+			return nil, errors.Wrap(err, "decode x coordinate")
+		}
+
+		y, err := decodeKeyParameter(k.Y)
+		if err != nil {
+			return nil, errors.Wrap(err, "decode y coordinate")
+		}
+
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on curve")
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, errors.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// Thumbprint computes RFC 7638 JWK thumbprint from required members of the key, ordered lexicographically.
+func (k *JSONWebKey) Thumbprint() (string, error) {
+	var members interface{}
+	switch k.Kty {
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{E: k.E, Kty: k.Kty, N: k.N}
+	case "EC":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{Crv: k.Crv, Kty: k.Kty, X: k.X, Y: k.Y}
+	default:
+		return "", errors.Errorf("unsupported key type %q", k.Kty)
+	}
+
+	canonical, err := json.Marshal(members)
+	if err != nil {
+		return "", errors.Wrap(err, "marshal key members")
+	}
+
+	sum := sha256.Sum256(canonical)
+
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+// JSONWebKeySet is set of public keys, RFC 7517 section 5.
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// Key returns key with given ID. If key ID is empty and set has single key, that key is returned.
+func (s *JSONWebKeySet) Key(kid string) *JSONWebKey {
+	if kid == "" && len(s.Keys) == 1 {
+		return &s.Keys[0]
+	}
+
+	for i := range s.Keys {
+		if s.Keys[i].Kid == kid {
+			return &s.Keys[i]
+		}
+	}
+
+	return nil
+}
+
+func decodeKeyParameter(value string) (*big.Int, error) {
+	if value == "" {
+		return nil, errors.New("parameter is missing")
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(decoded), nil
+}
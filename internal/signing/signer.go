@@ -0,0 +1,110 @@
+package signing
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/damejeras/auth/internal/app"
+	"github.com/golang-jwt/jwt"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+)
+
+// TypeLogoutToken is set in "typ" header of logout tokens, so they can not be used as tokens of another kind.
+const TypeLogoutToken = "logout+jwt"
+
+// Signer signs JSON Web Tokens issued by this server.
+type Signer struct {
+	issuer string
+	keyID  string
+	key    *rsa.PrivateKey
+}
+
+func NewSigner(cfg *app.Config, logger *zerolog.Logger) (*Signer, error) {
+	var key *rsa.PrivateKey
+	var err error
+	if cfg.SigningConfig.KeyFile == "" {
+		logger.Warn().Msg("signing key file is not configured, generating ephemeral key")
+
+		key, err = rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			return nil, errors.Wrap(err, "generate key")
+		}
+	} else {
+		key, err = readKey(cfg.SigningConfig.KeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "read key")
+		}
+	}
+
+	return &Signer{
+		issuer: cfg.Oauth2Config.Issuer,
+		keyID:  thumbprint(&key.PublicKey),
+		key:    key,
+	}, nil
+}
+
+func (s *Signer) Issuer() string {
+	return s.issuer
+}
+
+// Sign signs claims as token of given type.
+func (s *Signer) Sign(typ string, claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = s.keyID
+	token.Header["typ"] = typ
+
+	return token.SignedString(s.key)
+}
+
+func readKey(path string) (*rsa.PrivateKey, error) {
+	keyBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return jwt.ParseRSAPrivateKeyFromPEM(keyBytes)
+}
+
+// thumbprint computes RFC 7638 JWK thumbprint, which is used as key ID.
+func thumbprint(key *rsa.PublicKey) string {
+	canonical := `{"e":"` + base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()) +
+		`","kty":"RSA","n":"` + base64.RawURLEncoding.EncodeToString(key.N.Bytes()) + `"}`
+	sum := sha256.Sum256([]byte(canonical))
+
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// HandleKeySetRequest writes JSON Web Key Set with public key, which clients can use to verify tokens
+// and to encrypt request objects.
+func (s *Signer) HandleKeySetRequest(w http.ResponseWriter, _ *http.Request) error {
+	keySet := JSONWebKeySet{
+		Keys: []JSONWebKey{
+			{
+				Kty: "RSA",
+				Use: "sig",
+				Alg: jwt.SigningMethodRS256.Alg(),
+				Kid: s.keyID,
+				N:   base64.RawURLEncoding.EncodeToString(s.key.PublicKey.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.PublicKey.E)).Bytes()),
+			},
+			{
+				Kty: "RSA",
+				Use: "enc",
+				Alg: algRSAOAEP256,
+				Kid: s.keyID,
+				N:   base64.RawURLEncoding.EncodeToString(s.key.PublicKey.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.key.PublicKey.E)).Bytes()),
+			},
+		},
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+
+	return json.NewEncoder(w).Encode(keySet)
+}
@@ -0,0 +1,82 @@
+package dynamo
+
+import (
+	"context"
+
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/models"
+)
+
+type extensionContextKey struct{}
+
+type scopeContextKey struct{}
+
+// ExtensionFunc is extension claim value, which is resolved when token is created. It allows binding claims,
+// which are not known before the request is served. Nil value is not attached.
+type ExtensionFunc func() interface{}
+
+// WithExtension returns context, which makes token store attach extension claim to tokens created with it.
+// It allows binding claims to tokens, which are created by oauth2 manager.
+func WithExtension(ctx context.Context, key string, value interface{}) context.Context {
+	extension := make(map[string]interface{})
+	for k, v := range ContextExtension(ctx) {
+		extension[k] = v
+	}
+
+	extension[key] = value
+
+	return context.WithValue(ctx, extensionContextKey{}, extension)
+}
+
+// WithScope returns context, which makes token store set scope of access tokens created with it.
+// It allows narrowing scope of tokens, which oauth2 manager issues with scope of authorization code.
+func WithScope(ctx context.Context, scope string) context.Context {
+	return context.WithValue(ctx, scopeContextKey{}, scope)
+}
+
+// withContextScope sets scope from context to access token, authorization codes are left intact.
+func withContextScope(ctx context.Context, info oauth2.TokenInfo) {
+	if scope, ok := ctx.Value(scopeContextKey{}).(string); ok && info.GetAccess() != "" {
+		info.SetScope(scope)
+	}
+}
+
+// ContextExtension returns extension claims, which are attached to tokens created with the context.
+func ContextExtension(ctx context.Context) map[string]interface{} {
+	extension, _ := ctx.Value(extensionContextKey{}).(map[string]interface{})
+
+	return extension
+}
+
+// withContextExtension attaches extension claims from context to the token.
+// Tokens created by oauth2 manager are models.Token, so they are converted to Token first.
+func withContextExtension(ctx context.Context, info oauth2.TokenInfo) oauth2.TokenInfo {
+	extension := ContextExtension(ctx)
+	if len(extension) == 0 {
+		return info
+	}
+
+	token, ok := info.(*Token)
+	if !ok {
+		model, ok := info.(*models.Token)
+		if !ok {
+			return info
+		}
+
+		token = &Token{Token: *model}
+	}
+
+	for key, value := range extension {
+		if resolve, ok := value.(ExtensionFunc); ok {
+			value = resolve()
+		}
+
+		if value == nil {
+			continue
+		}
+
+		token.SetExtension(key, value)
+	}
+
+	return token
+}
@@ -3,6 +3,14 @@ package dynamo
 import (
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"time"
+)
+
+const (
+	indexClientUser = "ClientUserIndex"
+	indexSession    = "SessionIndex"
+
+	indexPollInterval = 5 * time.Second
 )
 
 func (ts *tokenStore) runMigrations() error {
@@ -31,11 +39,11 @@ func (ts *tokenStore) runMigrations() error {
 		}
 	}
 
-	return nil
+	return ts.createBasicIndexes()
 }
 
 func (ts *tokenStore) createSingleTable(name string) error {
-	_, err := ts.dbClient.CreateTable(&dynamodb.CreateTableInput{
+	input := &dynamodb.CreateTableInput{
 		AttributeDefinitions: []*dynamodb.AttributeDefinition{
 			{AttributeName: aws.String("ID"), AttributeType: aws.String("S")},
 		},
@@ -47,7 +55,141 @@ func (ts *tokenStore) createSingleTable(name string) error {
 			WriteCapacityUnits: aws.Int64(10),
 		},
 		TableName: aws.String(name),
-	})
+	}
+
+	// indexes are created with the table, as only one index can be added to existing table at a time
+	if name == ts.tables.BasicCname {
+		for _, index := range basicIndexes() {
+			input.AttributeDefinitions = append(input.AttributeDefinitions, index.attributes...)
+			input.GlobalSecondaryIndexes = append(input.GlobalSecondaryIndexes, index.index)
+		}
+	}
+
+	_, err := ts.dbClient.CreateTable(input)
 
 	return err
 }
+
+type basicIndex struct {
+	index      *dynamodb.GlobalSecondaryIndex
+	attributes []*dynamodb.AttributeDefinition
+}
+
+// basicIndexes are indexes on basic table, so tokens can be found by client and user, or by session.
+func basicIndexes() []basicIndex {
+	return []basicIndex{
+		{
+			index: &dynamodb.GlobalSecondaryIndex{
+				IndexName: aws.String(indexClientUser),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{AttributeName: aws.String("ClientID"), KeyType: aws.String("HASH")},
+					{AttributeName: aws.String("UserID"), KeyType: aws.String("RANGE")},
+				},
+				Projection: &dynamodb.Projection{
+					ProjectionType: aws.String("KEYS_ONLY"),
+				},
+				ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+					ReadCapacityUnits:  aws.Int64(5),
+					WriteCapacityUnits: aws.Int64(10),
+				},
+			},
+			attributes: []*dynamodb.AttributeDefinition{
+				{AttributeName: aws.String("ClientID"), AttributeType: aws.String("S")},
+				{AttributeName: aws.String("UserID"), AttributeType: aws.String("S")},
+			},
+		},
+		{
+			index: &dynamodb.GlobalSecondaryIndex{
+				IndexName: aws.String(indexSession),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{AttributeName: aws.String("SessionID"), KeyType: aws.String("HASH")},
+				},
+				Projection: &dynamodb.Projection{
+					ProjectionType: aws.String("KEYS_ONLY"),
+				},
+				ProvisionedThroughput: &dynamodb.ProvisionedThroughput{
+					ReadCapacityUnits:  aws.Int64(5),
+					WriteCapacityUnits: aws.Int64(10),
+				},
+			},
+			attributes: []*dynamodb.AttributeDefinition{
+				{AttributeName: aws.String("SessionID"), AttributeType: aws.String("S")},
+			},
+		},
+	}
+}
+
+// createBasicIndexes adds indexes, which basic table created by previous versions does not have. Only one index
+// can be created at a time, so next index is added once table and previously added indexes are active.
+func (ts *tokenStore) createBasicIndexes() error {
+	for _, index := range basicIndexes() {
+		table, err := ts.waitForBasicTable()
+		if err != nil {
+			return err
+		}
+
+		if hasIndex(table, *index.index.IndexName) {
+			continue
+		}
+
+		if _, err := ts.dbClient.UpdateTable(&dynamodb.UpdateTableInput{
+			TableName:            aws.String(ts.tables.BasicCname),
+			AttributeDefinitions: index.attributes,
+			GlobalSecondaryIndexUpdates: []*dynamodb.GlobalSecondaryIndexUpdate{
+				{
+					Create: &dynamodb.CreateGlobalSecondaryIndexAction{
+						IndexName:             index.index.IndexName,
+						KeySchema:             index.index.KeySchema,
+						Projection:            index.index.Projection,
+						ProvisionedThroughput: index.index.ProvisionedThroughput,
+					},
+				},
+			},
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// waitForBasicTable returns description of basic table once the table and all of its indexes are active.
+func (ts *tokenStore) waitForBasicTable() (*dynamodb.TableDescription, error) {
+	input := &dynamodb.DescribeTableInput{
+		TableName: aws.String(ts.tables.BasicCname),
+	}
+
+	for {
+		if err := ts.dbClient.WaitUntilTableExists(input); err != nil {
+			return nil, err
+		}
+
+		description, err := ts.dbClient.DescribeTable(input)
+		if err != nil {
+			return nil, err
+		}
+
+		active := true
+		for _, index := range description.Table.GlobalSecondaryIndexes {
+			if aws.StringValue(index.IndexStatus) != dynamodb.IndexStatusActive {
+				active = false
+			}
+		}
+
+		if active {
+			return description.Table, nil
+		}
+
+		time.Sleep(indexPollInterval)
+	}
+}
+
+func hasIndex(table *dynamodb.TableDescription, name string) bool {
+	for _, index := range table.GlobalSecondaryIndexes {
+		if aws.StringValue(index.IndexName) == name {
+			return true
+		}
+	}
+
+	return false
+}
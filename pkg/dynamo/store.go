@@ -9,16 +9,27 @@ import (
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/go-oauth2/oauth2/v4"
-	"github.com/go-oauth2/oauth2/v4/models"
 	"gopkg.in/mgo.v2/bson"
 )
 
+// ClaimSessionID is extension claim of session token was issued in. Tokens are indexed by it,
+// so tokens issued in the session can be removed when it ends.
+const ClaimSessionID = "sid"
+
+// TokenStore is oauth2.TokenStore which is also able to remove all tokens issued to client on behalf of user,
+// or issued in the session.
+type TokenStore interface {
+	oauth2.TokenStore
+	RemoveByClientAndUser(ctx context.Context, clientID, userID string) error
+	RemoveBySession(ctx context.Context, sessionID string) error
+}
+
 type tokenStore struct {
 	tables   TableConfig
 	dbClient *dynamodb.DynamoDB
 }
 
-func NewTokenStore(client *dynamodb.DynamoDB, options ...Option) (oauth2.TokenStore, error) {
+func NewTokenStore(client *dynamodb.DynamoDB, options ...Option) (TokenStore, error) {
 	store := tokenStore{
 		tables:   DefaultTableConfig,
 		dbClient: client,
@@ -36,6 +47,9 @@ func NewTokenStore(client *dynamodb.DynamoDB, options ...Option) (oauth2.TokenSt
 }
 
 func (ts *tokenStore) Create(ctx context.Context, info oauth2.TokenInfo) error {
+	info = withContextExtension(ctx, info)
+	withContextScope(ctx, info)
+
 	if code := info.GetCode(); code != "" {
 		err := createWithAuthorizationCode(ctx, ts, info, "")
 		if err != nil {
@@ -100,6 +114,58 @@ func (ts *tokenStore) RemoveByRefresh(ctx context.Context, refresh string) error
 	return nil
 }
 
+func (ts *tokenStore) RemoveByClientAndUser(ctx context.Context, clientID, userID string) error {
+	return ts.removeByIndex(ctx, &dynamodb.QueryInput{
+		TableName: aws.String(ts.tables.BasicCname),
+		IndexName: aws.String(indexClientUser),
+		KeyConditions: map[string]*dynamodb.Condition{
+			"ClientID": {
+				ComparisonOperator: aws.String("EQ"),
+				AttributeValueList: []*dynamodb.AttributeValue{{S: aws.String(clientID)}},
+			},
+			"UserID": {
+				ComparisonOperator: aws.String("EQ"),
+				AttributeValueList: []*dynamodb.AttributeValue{{S: aws.String(userID)}},
+			},
+		},
+	})
+}
+
+func (ts *tokenStore) RemoveBySession(ctx context.Context, sessionID string) error {
+	return ts.removeByIndex(ctx, &dynamodb.QueryInput{
+		TableName: aws.String(ts.tables.BasicCname),
+		IndexName: aws.String(indexSession),
+		KeyConditions: map[string]*dynamodb.Condition{
+			"SessionID": {
+				ComparisonOperator: aws.String("EQ"),
+				AttributeValueList: []*dynamodb.AttributeValue{{S: aws.String(sessionID)}},
+			},
+		},
+	})
+}
+
+// removeByIndex removes basic items found by index query, so their access and refresh tokens are no longer resolved.
+func (ts *tokenStore) removeByIndex(ctx context.Context, input *dynamodb.QueryInput) error {
+	var removeErr error
+	err := ts.dbClient.QueryPagesWithContext(ctx, input, func(output *dynamodb.QueryOutput, lastPage bool) bool {
+		for _, item := range output.Items {
+			if _, removeErr = ts.dbClient.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
+				Key:       map[string]*dynamodb.AttributeValue{"ID": item["ID"]},
+				TableName: aws.String(ts.tables.BasicCname),
+			}); removeErr != nil {
+				return false
+			}
+		}
+
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	return removeErr
+}
+
 func (ts *tokenStore) GetByCode(ctx context.Context, code string) (oauth2.TokenInfo, error) {
 	return ts.getData(ctx, code)
 }
@@ -161,11 +227,23 @@ func createWithAuthorizationCode(ctx context.Context, tokenStorage *tokenStore,
 		TableName: aws.String(tokenStorage.tables.BasicCname),
 		Item: map[string]*dynamodb.AttributeValue{
 			"ID":        {S: aws.String(code)},
+			"ClientID":  {S: aws.String(info.GetClientID())},
 			"Data":      {B: data},
 			"ExpiredAt": {S: &exp},
 		},
 	}
 
+	// index keys can not be empty, so tokens issued without user are left out of the index
+	if userID := info.GetUserID(); userID != "" {
+		params.Item["UserID"] = &dynamodb.AttributeValue{S: aws.String(userID)}
+	}
+
+	if token, ok := info.(*Token); ok {
+		if sessionID, _ := token.GetExtension(ClaimSessionID).(string); sessionID != "" {
+			params.Item["SessionID"] = &dynamodb.AttributeValue{S: aws.String(sessionID)}
+		}
+	}
+
 	_, err = tokenStorage.dbClient.PutItemWithContext(ctx, params)
 
 	return err
@@ -243,13 +321,13 @@ func (ts *tokenStore) getData(ctx context.Context, basicID string) (oauth2.Token
 		return nil, err
 	}
 
-	var tm models.Token
-	err = json.Unmarshal(b.Data, &tm)
+	tm := NewToken()
+	err = json.Unmarshal(b.Data, tm)
 	if err != nil {
 		return nil, err
 	}
 
-	return &tm, nil
+	return tm, nil
 }
 
 func (ts *tokenStore) getBasicID(ctx context.Context, cname, token string) (string, error) {
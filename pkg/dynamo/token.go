@@ -0,0 +1,35 @@
+package dynamo
+
+import (
+	"github.com/go-oauth2/oauth2/v4"
+	"github.com/go-oauth2/oauth2/v4/models"
+)
+
+// Token is oauth2 token with extension claims, which are stored along with the token and loaded back with it.
+// Tokens loaded from the store are always of this type.
+type Token struct {
+	models.Token
+	Extension map[string]interface{} `json:"Extension,omitempty"`
+}
+
+func NewToken() *Token {
+	return &Token{}
+}
+
+func (t *Token) New() oauth2.TokenInfo {
+	return NewToken()
+}
+
+// SetExtension attaches extension claim to the token.
+func (t *Token) SetExtension(key string, value interface{}) {
+	if t.Extension == nil {
+		t.Extension = make(map[string]interface{})
+	}
+
+	t.Extension[key] = value
+}
+
+// GetExtension returns extension claim of the token or nil if token does not have it.
+func (t *Token) GetExtension(key string) interface{} {
+	return t.Extension[key]
+}